@@ -1,14 +1,20 @@
 package adminapi
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/spf13/cast"
 	"github.com/talkincode/toughradius/v9/internal/domain"
 	"github.com/talkincode/toughradius/v9/internal/webserver"
+	"github.com/talkincode/toughradius/v9/pkg/common"
 )
 
 // ListAccounting retrieves the accounting logs table
@@ -29,7 +35,7 @@ import (
 // @Success 200 {object} ListResponse
 // @Router /api/v1/accounting [get]
 func ListAccounting(c echo.Context) error {
-	db := GetDB(c)
+	db := GetRequestDB(c)
 
 	page, _ := strconv.Atoi(c.QueryParam("page"))
 	perPage, _ := strconv.Atoi(c.QueryParam("perPage"))
@@ -133,6 +139,225 @@ func GetAccounting(c echo.Context) error {
 	return ok(c, record)
 }
 
+// accountingImportError describes a rejected row of an accounting import
+type accountingImportError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// accountingImportResult summarizes an accounting import
+type accountingImportResult struct {
+	Total      int                     `json:"total"`
+	Imported   int                     `json:"imported"`
+	Duplicates int                     `json:"duplicates"`
+	Errors     []accountingImportError `json:"errors"`
+}
+
+// ImportAccounting imports historical accounting records from a CSV or JSON lines upload.
+// Rows whose acct_session_id already exists are skipped so imports can be repeated.
+// @Summary import accounting records
+// @Tags Accounting
+// @Param upload formData file true "CSV or JSON lines file"
+// @Success 200 {object} accountingImportResult
+// @Router /api/v1/radius/accounting/import [post]
+func ImportAccounting(c echo.Context) error {
+	rows, malformed, err := readAccountingImport(c)
+	if err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_FILE", "Unable to read import file", err.Error())
+	}
+
+	result := accountingImportResult{Total: len(rows), Errors: make([]accountingImportError, 0)}
+
+	records := make([]*domain.RadiusAccounting, 0, len(rows))
+	rowNumbers := make([]int, 0, len(rows))
+	for i, row := range rows {
+		if msg, bad := malformed[i]; bad {
+			result.Errors = append(result.Errors, accountingImportError{Row: i + 1, Message: msg})
+			continue
+		}
+		record, err := accountingFromImportRow(row)
+		if err != nil {
+			result.Errors = append(result.Errors, accountingImportError{Row: i + 1, Message: err.Error()})
+			continue
+		}
+		records = append(records, record)
+		rowNumbers = append(rowNumbers, i+1)
+	}
+
+	// Skip sessions already stored or repeated within the file
+	seen := make(map[string]bool)
+	sessionIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		sessionIDs = append(sessionIDs, record.AcctSessionId)
+	}
+	db := GetDB(c)
+	for start := 0; start < len(sessionIDs); start += 500 {
+		end := start + 500
+		if end > len(sessionIDs) {
+			end = len(sessionIDs)
+		}
+		var existing []string
+		if err := db.Model(&domain.RadiusAccounting{}).
+			Where("acct_session_id IN ?", sessionIDs[start:end]).
+			Pluck("acct_session_id", &existing).Error; err != nil {
+			return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to query accounting records", err.Error())
+		}
+		for _, id := range existing {
+			seen[id] = true
+		}
+	}
+
+	inserts := make([]*domain.RadiusAccounting, 0, len(records))
+	for _, record := range records {
+		if seen[record.AcctSessionId] {
+			result.Duplicates++
+			continue
+		}
+		seen[record.AcctSessionId] = true
+		inserts = append(inserts, record)
+	}
+
+	if len(inserts) > 0 {
+		if err := db.CreateInBatches(inserts, 100).Error; err != nil {
+			return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to import accounting records", err.Error())
+		}
+	}
+	result.Imported = len(inserts)
+
+	return ok(c, result)
+}
+
+// readAccountingImport reads the rows of an accounting upload. JSON lines
+// files are read here instead of by webserver.ImportData, which stops at
+// the first malformed line: such lines are kept as nil rows and described
+// in malformed by row index, so they are reported and the import goes on.
+func readAccountingImport(c echo.Context) (rows []map[string]interface{}, malformed map[int]string, err error) {
+	file, err := c.FormFile("upload")
+	if err != nil {
+		return nil, nil, err
+	}
+	if !strings.HasSuffix(file.Filename, "json") && !strings.HasSuffix(file.Filename, "jsonl") {
+		rows, err = webserver.ImportData(c, "accounting")
+		return rows, nil, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = src.Close() }() //nolint:errcheck
+
+	malformed = make(map[int]string)
+	reader := bufio.NewReader(src)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return nil, nil, readErr
+		}
+		if len(strings.TrimSpace(string(line))) > 0 {
+			row := make(map[string]interface{})
+			if err := common.JsonUnmarshal(line, &row); err != nil {
+				malformed[len(rows)] = fmt.Sprintf("malformed JSON: %v", err)
+				row = nil
+			}
+			rows = append(rows, row)
+		}
+		if readErr != nil {
+			return rows, malformed, nil
+		}
+	}
+}
+
+// accountingFromImportRow converts an imported row into an accounting record,
+// validating required fields and counters
+func accountingFromImportRow(row map[string]interface{}) (*domain.RadiusAccounting, error) {
+	str := func(key string) string {
+		return strings.TrimSpace(cast.ToString(row[key]))
+	}
+	num := func(key string) (int64, error) {
+		value := str(key)
+		if value == "" {
+			return 0, nil
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%s must be a non-negative integer", key)
+		}
+		return n, nil
+	}
+
+	record := &domain.RadiusAccounting{
+		ID:                  common.UUIDint64(),
+		Username:            str("username"),
+		AcctSessionId:       str("acct_session_id"),
+		NasId:               str("nas_id"),
+		NasAddr:             str("nas_addr"),
+		NasPaddr:            str("nas_paddr"),
+		FramedIpaddr:        str("framed_ipaddr"),
+		FramedNetmask:       str("framed_netmask"),
+		FramedIpv6Prefix:    str("framed_ipv6_prefix"),
+		FramedIpv6Address:   str("framed_ipv6_address"),
+		DelegatedIpv6Prefix: str("delegated_ipv6_prefix"),
+		MacAddr:             str("mac_addr"),
+		NasClass:            str("nas_class"),
+		NasPortId:           str("nas_port_id"),
+	}
+	if record.Username == "" {
+		return nil, fmt.Errorf("username is required")
+	}
+	if record.AcctSessionId == "" {
+		return nil, fmt.Errorf("acct_session_id is required")
+	}
+
+	startValue := str("acct_start_time")
+	if startValue == "" {
+		return nil, fmt.Errorf("acct_start_time is required")
+	}
+	startTime, err := parseStartTimeInput(startValue, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid acct_start_time")
+	}
+	record.AcctStartTime = startTime
+
+	counters := []struct {
+		key string
+		set func(n int64)
+	}{
+		{"acct_session_time", func(n int64) { record.AcctSessionTime = int(n) }},
+		{"acct_input_total", func(n int64) { record.AcctInputTotal = n }},
+		{"acct_output_total", func(n int64) { record.AcctOutputTotal = n }},
+		{"acct_input_packets", func(n int64) { record.AcctInputPackets = int(n) }},
+		{"acct_output_packets", func(n int64) { record.AcctOutputPackets = int(n) }},
+		{"nas_port", func(n int64) { record.NasPort = n }},
+		{"nas_port_type", func(n int64) { record.NasPortType = int(n) }},
+		{"service_type", func(n int64) { record.ServiceType = int(n) }},
+		{"session_timeout", func(n int64) { record.SessionTimeout = int(n) }},
+	}
+	for _, counter := range counters {
+		n, err := num(counter.key)
+		if err != nil {
+			return nil, err
+		}
+		counter.set(n)
+	}
+
+	// Derive the stop time from the session duration when not provided
+	record.AcctStopTime = startTime.Add(time.Duration(record.AcctSessionTime) * time.Second)
+	if stopValue := str("acct_stop_time"); stopValue != "" {
+		stopTime, err := parseStartTimeInput(stopValue, time.Time{})
+		if err != nil {
+			return nil, fmt.Errorf("invalid acct_stop_time")
+		}
+		if stopTime.Before(startTime) {
+			return nil, fmt.Errorf("acct_stop_time is before acct_start_time")
+		}
+		record.AcctStopTime = stopTime
+	}
+	record.LastUpdate = record.AcctStopTime
+
+	return record, nil
+}
+
 // parseFlexibleTime parses time string in RFC3339 or datetime-local format
 func parseFlexibleTime(s string) (time.Time, error) {
 	// Try RFC3339 first (e.g., "2025-11-01T21:16:00Z")
@@ -163,4 +388,5 @@ func escapeLikePattern(s string) string {
 func registerAccountingRoutes() {
 	webserver.ApiGET("/accounting", ListAccounting)
 	webserver.ApiGET("/accounting/:id", GetAccounting)
+	webserver.ApiPOST("/radius/accounting/import", ImportAccounting, invalidatesCache(cacheDashboardStats))
 }
@@ -1,7 +1,9 @@
 package adminapi
 
 import (
+	"bytes"
 	"encoding/json"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -316,3 +318,155 @@ func TestAccountingFilters(t *testing.T) {
 		})
 	}
 }
+
+// newImportRequest builds a multipart upload request for the import endpoint
+func newImportRequest(t *testing.T, filename, content string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("upload", filename)
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/radius/accounting/import", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestImportAccounting(t *testing.T) {
+	db, e, appCtx := CreateTestAppContext(t)
+
+	require.NoError(t, db.Create(&domain.RadiusAccounting{
+		ID:            1,
+		Username:      "existing",
+		AcctSessionId: "sess-existing",
+		AcctStartTime: time.Now().Add(-2 * time.Hour),
+		AcctStopTime:  time.Now().Add(-time.Hour),
+	}).Error)
+
+	csvContent := "username,acct_session_id,acct_start_time,acct_stop_time,acct_session_time,acct_input_total,acct_output_total\n" +
+		"alice,sess-a,2025-01-01 10:00:00,2025-01-01 11:00:00,3600,1000,2000\n" +
+		"bob,sess-b,2025-01-01 12:00:00,,600,500,700\n" +
+		"alice,sess-a,2025-01-01 10:00:00,2025-01-01 11:00:00,3600,1000,2000\n" +
+		"carol,sess-existing,2025-01-01 09:00:00,,60,1,1\n" +
+		",sess-c,2025-01-01 09:00:00,,60,1,1\n" +
+		"dave,sess-d,not-a-time,,60,1,1\n" +
+		"erin,sess-e,2025-01-01 09:00:00,,60,-5,1\n" +
+		"frank,sess-f,2025-01-01 09:00:00,,60,abc,1\n"
+
+	rec := httptest.NewRecorder()
+	c := CreateTestContext(e, db, newImportRequest(t, "accounting.csv", csvContent), rec, appCtx)
+	require.NoError(t, ImportAccounting(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	dataBytes, _ := json.Marshal(response.Data)
+	var result accountingImportResult
+	require.NoError(t, json.Unmarshal(dataBytes, &result))
+
+	assert.Equal(t, 8, result.Total)
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, 2, result.Duplicates)
+	require.Len(t, result.Errors, 4)
+	assert.Equal(t, 5, result.Errors[0].Row)
+	assert.Contains(t, result.Errors[0].Message, "username")
+	assert.Contains(t, result.Errors[1].Message, "acct_start_time")
+	assert.Contains(t, result.Errors[2].Message, "acct_input_total")
+	assert.Contains(t, result.Errors[3].Message, "acct_input_total")
+
+	var count int64
+	db.Model(&domain.RadiusAccounting{}).Where("acct_session_id = ?", "sess-a").Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	var bob domain.RadiusAccounting
+	require.NoError(t, db.Where("acct_session_id = ?", "sess-b").First(&bob).Error)
+	assert.Equal(t, int64(500), bob.AcctInputTotal)
+	assert.Equal(t, 600*time.Second, bob.AcctStopTime.Sub(bob.AcctStartTime))
+
+	t.Run("JSON upload", func(t *testing.T) {
+		jsonContent := `{"username":"gina","acct_session_id":"sess-g","acct_start_time":"2025-02-01T08:00:00Z","acct_input_total":42}` + "\n"
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, newImportRequest(t, "accounting.json", jsonContent), rec, appCtx)
+		require.NoError(t, ImportAccounting(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var gina domain.RadiusAccounting
+		require.NoError(t, db.Where("acct_session_id = ?", "sess-g").First(&gina).Error)
+		assert.Equal(t, int64(42), gina.AcctInputTotal)
+	})
+
+	t.Run("Malformed JSON line", func(t *testing.T) {
+		jsonContent := `{"username":"hank","acct_session_id":"sess-h","acct_start_time":"2025-03-01"}` + "\n" +
+			`{"username":"ivy","acct_session_id":` + "\n" +
+			"\n" +
+			`{"username":"jack","acct_session_id":"sess-j","acct_start_time":"2025-03-01T08:00:00Z"}`
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, newImportRequest(t, "accounting.json", jsonContent), rec, appCtx)
+		require.NoError(t, ImportAccounting(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		dataBytes, _ := json.Marshal(response.Data)
+		var result accountingImportResult
+		require.NoError(t, json.Unmarshal(dataBytes, &result))
+
+		assert.Equal(t, 3, result.Total)
+		assert.Equal(t, 2, result.Imported, "lines after a malformed one are still imported")
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, 2, result.Errors[0].Row)
+		assert.Contains(t, result.Errors[0].Message, "malformed JSON")
+
+		// A date without a time starts at midnight
+		var hank domain.RadiusAccounting
+		require.NoError(t, db.Where("acct_session_id = ?", "sess-h").First(&hank).Error)
+		assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local), hank.AcctStartTime.In(time.Local))
+	})
+
+	t.Run("Missing upload", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/radius/accounting/import", nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		require.NoError(t, ImportAccounting(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestImportAccountingInvalidatesDashboardStats(t *testing.T) {
+	db, e, appCtx := CreateTestAppContext(t)
+	apiResponseCache.invalidate(cacheDashboardStats)
+	t.Cleanup(func() { apiResponseCache.invalidate(cacheDashboardStats) })
+	require.NoError(t, appCtx.ConfigMgr().Set("system", "DashboardStatsCacheSeconds", "60"))
+
+	getStats := cachedResponse(cacheDashboardStats)(GetDashboardStats)
+	todayAcctCount := func(t *testing.T) (string, int64) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard/stats", nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, getStats(CreateTestContext(e, db, req, rec, appCtx)))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		dataBytes, _ := json.Marshal(response.Data) //nolint:errcheck
+		var stats DashboardStats
+		require.NoError(t, json.Unmarshal(dataBytes, &stats))
+		return rec.Header().Get("X-Cache"), stats.TodayAcctCount
+	}
+
+	state, count := todayAcctCount(t)
+	assert.Equal(t, "MISS", state)
+	assert.Equal(t, int64(0), count)
+
+	start := time.Now().Format("2006-01-02 15:04:05")
+	csvContent := "username,acct_session_id,acct_start_time\nalice,sess-today," + start + "\n"
+	rec := httptest.NewRecorder()
+	c := CreateTestContext(e, db, newImportRequest(t, "accounting.csv", csvContent), rec, appCtx)
+	require.NoError(t, invalidatesCache(cacheDashboardStats)(ImportAccounting)(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	state, count = todayAcctCount(t)
+	assert.Equal(t, "MISS", state, "an import drops the cached dashboard stats")
+	assert.Equal(t, int64(1), count)
+}
@@ -17,4 +17,6 @@ func Init(appCtx app.AppContext) {
 	registerSettingsRoutes()
 	registerNodesRoutes()
 	registerOperatorsRoutes()
+	registerSearchRoutes()
+	registerMigrationRoutes()
 }
@@ -19,6 +19,12 @@ func GetDB(c echo.Context) *gorm.DB {
 	return GetAppContext(c).DB()
 }
 
+// GetRequestDB gets the database connection bound to the request context, so
+// an aborted HTTP request cancels its queries
+func GetRequestDB(c echo.Context) *gorm.DB {
+	return GetDB(c).WithContext(c.Request().Context())
+}
+
 // GetConfig gets the configuration from echo context
 func GetConfig(c echo.Context) *app.ConfigManager {
 	return GetAppContext(c).ConfigMgr()
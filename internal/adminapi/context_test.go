@@ -0,0 +1,33 @@
+package adminapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRequestDBCancelsWithRequest(t *testing.T) {
+	db, e, appCtx := CreateTestAppContext(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/accounting", nil).WithContext(ctx)
+	c := CreateTestContext(e, db, req, httptest.NewRecorder(), appCtx)
+
+	// Simulate the client disconnecting while a slow query runs
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	var seq []int64
+	err := GetRequestDB(c).Raw(
+		"WITH RECURSIVE seq(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM seq WHERE n < 1000000000) SELECT n FROM seq",
+	).Scan(&seq).Error
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second, "the query should stop when the request is cancelled")
+	assert.Less(t, len(seq), 1000000000)
+}
@@ -61,7 +61,7 @@ const (
 // @Success 200 {object} DashboardStats
 // @Router /api/v1/dashboard/stats [get]
 func GetDashboardStats(c echo.Context) error {
-	db := GetDB(c).WithContext(c.Request().Context())
+	db := GetRequestDB(c)
 	now := time.Now()
 	todayStart := startOfDay(now)
 
@@ -109,7 +109,7 @@ func GetDashboardStats(c echo.Context) error {
 
 // registerDashboardRoutes registers the dashboard routes
 func registerDashboardRoutes() {
-	webserver.ApiGET("/dashboard/stats", GetDashboardStats)
+	webserver.ApiGET("/dashboard/stats", GetDashboardStats, cachedResponse(cacheDashboardStats))
 }
 
 func fetchAuthTrend(db *gorm.DB, now time.Time) []DashboardAuthTrendPoint {
@@ -7,20 +7,121 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
-func parsePagination(c echo.Context) (int, int) {
+// parsePagination reads the page and the page size carried by sizeParam,
+// falling back to defaultSize when the size is missing or above maxSize
+func parsePagination(c echo.Context, sizeParam string, defaultSize, maxSize int) (int, int) {
 	page, err := strconv.Atoi(c.QueryParam("page"))
 	if err != nil || page < 1 {
 		page = 1
 	}
-	pageSize, err := strconv.Atoi(c.QueryParam("pageSize"))
-	if err != nil || pageSize < 1 || pageSize > 200 {
-		pageSize = 20
+	pageSize, err := strconv.Atoi(c.QueryParam(sizeParam))
+	if err != nil || pageSize < 1 || pageSize > maxSize {
+		pageSize = defaultSize
 	}
 	return page, pageSize
 }
 
+// listOptions describes how listQuery paginates and sorts a collection
+type listOptions struct {
+	SortFields      map[string]bool // Whitelist of sortable columns
+	DefaultSort     string          // Column used when sort is missing or not whitelisted
+	DefaultOrder    string          // "ASC" or "DESC"
+	PageSizeParam   string          // Query parameter carrying the page size, defaults to "pageSize"
+	DefaultPageSize int             // Page size used when the parameter is missing or out of range
+	MaxPageSize     int             // Largest accepted page size
+}
+
+// listPage is a single page of results returned by listQuery
+type listPage[T any] struct {
+	Items    []T
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// listQuery counts and fetches one page of query using the request's
+// page, page size, sort and order parameters. Sort columns outside the
+// whitelist fall back to the default to prevent SQL injection.
+func listQuery[T any](c echo.Context, query *gorm.DB, opts listOptions) (*listPage[T], error) {
+	sizeParam := opts.PageSizeParam
+	if sizeParam == "" {
+		sizeParam = "pageSize"
+	}
+	defaultSize := opts.DefaultPageSize
+	if defaultSize < 1 {
+		defaultSize = 20
+	}
+	maxSize := opts.MaxPageSize
+	if maxSize < 1 {
+		maxSize = 200
+	}
+	page, pageSize := parsePagination(c, sizeParam, defaultSize, maxSize)
+
+	sortField := c.QueryParam("sort")
+	if sortField == "" || !opts.SortFields[sortField] {
+		sortField = opts.DefaultSort
+	}
+	order := strings.ToUpper(c.QueryParam("order"))
+	if order != "ASC" && order != "DESC" {
+		order = opts.DefaultOrder
+	}
+	if order != "ASC" {
+		order = "DESC"
+	}
+
+	result := &listPage[T]{Page: page, PageSize: pageSize}
+	if err := query.Session(&gorm.Session{}).Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+
+	if sortField != "" {
+		query = query.Order(sortField + " " + order)
+	}
+	if err := query.Session(&gorm.Session{}).
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&result.Items).Error; err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// whereContainsFold restricts query to rows where any of the columns contains
+// term, ignoring case. LIKE wildcards in term are matched literally.
+func whereContainsFold(query *gorm.DB, term string, columns ...string) *gorm.DB {
+	pattern := "%" + escapeLikePattern(term) + "%"
+	isPostgres := strings.EqualFold(query.Name(), "postgres") //nolint:staticcheck
+	if !isPostgres {
+		pattern = strings.ToLower(pattern)
+	}
+
+	conditions := make([]string, 0, len(columns))
+	args := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		if isPostgres {
+			conditions = append(conditions, column+" ILIKE ? ESCAPE '\\'")
+		} else {
+			conditions = append(conditions, "LOWER("+column+") LIKE ? ESCAPE '\\'")
+		}
+		args = append(args, pattern)
+	}
+	return query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation
+func isUniqueViolation(err error) bool {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "UNIQUE constraint failed") || // SQLite
+		strings.Contains(msg, "SQLSTATE 23505") // Postgres unique_violation
+}
+
 func parseIDParam(c echo.Context, name string) (int64, error) {
 	param := c.Param(name)
 	if param == "" {
@@ -36,6 +137,29 @@ func parseIDParam(c echo.Context, name string) (int64, error) {
 	return id, nil
 }
 
+// parseStartTimeInput parses a point in time or the start of a range. Unlike
+// parseTimeInput, a date without a time is taken as its midnight.
+func parseStartTimeInput(value string, fallback time.Time) (time.Time, error) {
+	if day, ok := parseDateOnly(value); ok {
+		return day, nil
+	}
+	return parseTimeInput(value, fallback)
+}
+
+// parseEndTimeInput parses the exclusive end of a range. A date without a
+// time is taken as the next midnight, so the whole day is included.
+func parseEndTimeInput(value string, fallback time.Time) (time.Time, error) {
+	if day, ok := parseDateOnly(value); ok {
+		return day.AddDate(0, 0, 1), nil
+	}
+	return parseTimeInput(value, fallback)
+}
+
+func parseDateOnly(value string) (time.Time, bool) {
+	day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(value), time.Local)
+	return day, err == nil
+}
+
 func parseTimeInput(value string, fallback time.Time) (time.Time, error) {
 	value = strings.TrimSpace(value)
 	if value == "" {
@@ -0,0 +1,110 @@
+package adminapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+)
+
+func TestListQuery(t *testing.T) {
+	db := setupTestDB(t)
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, db.Create(&domain.NetNode{ID: int64(i), Name: fmt.Sprintf("node-%d", 6-i)}).Error)
+	}
+
+	opts := listOptions{
+		SortFields:      map[string]bool{"id": true, "name": true},
+		DefaultSort:     "id",
+		DefaultOrder:    "DESC",
+		DefaultPageSize: 2,
+		MaxPageSize:     3,
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		expectedPage int
+		expectedSize int
+		expectedIDs  []int64
+	}{
+		{
+			name:         "Defaults",
+			query:        "",
+			expectedPage: 1,
+			expectedSize: 2,
+			expectedIDs:  []int64{5, 4},
+		},
+		{
+			name:         "Second page",
+			query:        "?page=2&pageSize=2",
+			expectedPage: 2,
+			expectedSize: 2,
+			expectedIDs:  []int64{3, 2},
+		},
+		{
+			name:         "Page size above max falls back to default",
+			query:        "?pageSize=50",
+			expectedPage: 1,
+			expectedSize: 2,
+			expectedIDs:  []int64{5, 4},
+		},
+		{
+			name:         "Invalid page falls back to first page",
+			query:        "?page=-3&pageSize=3",
+			expectedPage: 1,
+			expectedSize: 3,
+			expectedIDs:  []int64{5, 4, 3},
+		},
+		{
+			name:         "Whitelisted sort",
+			query:        "?sort=name&order=ASC&pageSize=3",
+			expectedPage: 1,
+			expectedSize: 3,
+			expectedIDs:  []int64{5, 4, 3},
+		},
+		{
+			name:         "Sort outside whitelist uses default",
+			query:        "?sort=id%3BDROP%20TABLE%20net_node&order=ASC",
+			expectedPage: 1,
+			expectedSize: 2,
+			expectedIDs:  []int64{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := setupTestEcho()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/network/nodes"+tt.query, nil)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			result, err := listQuery[domain.NetNode](c, db.Model(&domain.NetNode{}), opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, int64(5), result.Total)
+			assert.Equal(t, tt.expectedPage, result.Page)
+			assert.Equal(t, tt.expectedSize, result.PageSize)
+
+			ids := make([]int64, 0, len(result.Items))
+			for _, node := range result.Items {
+				ids = append(ids, node.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.Exec("CREATE UNIQUE INDEX uidx_test_nas_ipaddr ON net_nas (ipaddr)").Error)
+	createTestNas(db, "first", "10.3.0.1")
+
+	err := db.Create(&domain.NetNas{Name: "second", Ipaddr: "10.3.0.1"}).Error
+	require.Error(t, err)
+	assert.True(t, isUniqueViolation(err))
+	assert.False(t, isUniqueViolation(fmt.Errorf("connection refused")))
+}
@@ -0,0 +1,97 @@
+package adminapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/talkincode/toughradius/v9/internal/app"
+	"github.com/talkincode/toughradius/v9/internal/webserver"
+	"go.uber.org/zap"
+)
+
+// GetMigrationPlan reports the tables, columns and indexes the schema
+// migration would create, without changing the database
+//
+// @Summary preview pending database schema changes
+// @Tags DBMS
+// @Success 200 {object} app.MigrationPlan
+// @Router /api/v1/dbms/migration/plan [get]
+func GetMigrationPlan(c echo.Context) error {
+	currentOpr, err := resolveOperatorFromContext(c)
+	if err != nil {
+		return fail(c, http.StatusUnauthorized, "UNAUTHORIZED", "Unable to retrieve current user information", nil)
+	}
+	if currentOpr.Level != "super" {
+		return fail(c, http.StatusForbidden, "PERMISSION_DENIED", "Only super admins can review database migrations", nil)
+	}
+
+	plan, err := app.PlanDBMigration(GetDB(c))
+	if err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to compare database schema", err.Error())
+	}
+	return ok(c, plan)
+}
+
+// ApplyMigration runs the schema migration and returns the remaining plan
+//
+// @Summary apply pending database schema changes
+// @Tags DBMS
+// @Success 200 {object} app.MigrationPlan
+// @Router /api/v1/dbms/migration/apply [post]
+func ApplyMigration(c echo.Context) error {
+	currentOpr, err := resolveOperatorFromContext(c)
+	if err != nil {
+		return fail(c, http.StatusUnauthorized, "UNAUTHORIZED", "Unable to retrieve current user information", nil)
+	}
+	if currentOpr.Level != "super" {
+		return fail(c, http.StatusForbidden, "PERMISSION_DENIED", "Only super admins can apply database migrations", nil)
+	}
+
+	db := GetDB(c)
+	if err = app.ApplyDBMigration(db); errors.Is(err, app.ErrMigrationInProgress) {
+		return fail(c, http.StatusConflict, "MIGRATION_IN_PROGRESS", err.Error(), nil)
+	} else if err != nil {
+		return fail(c, http.StatusInternalServerError, "MIGRATION_FAILED", "Database migration failed", err.Error())
+	}
+	zap.L().Info("database migration applied from admin API")
+
+	plan, err := app.PlanDBMigration(db)
+	if err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to compare database schema", err.Error())
+	}
+	return ok(c, plan)
+}
+
+// SuggestTableIndexes suggests indexes for the commonly filtered columns of
+// a domain table that no existing index covers
+//
+// @Summary suggest missing indexes of a table
+// @Tags DBMS
+// @Param name path string true "Table name"
+// @Success 200 {array} app.IndexSuggestion
+// @Router /api/v1/dbms/tables/{name}/suggest-indexes [get]
+func SuggestTableIndexes(c echo.Context) error {
+	currentOpr, err := resolveOperatorFromContext(c)
+	if err != nil {
+		return fail(c, http.StatusUnauthorized, "UNAUTHORIZED", "Unable to retrieve current user information", nil)
+	}
+	if currentOpr.Level != "super" {
+		return fail(c, http.StatusForbidden, "PERMISSION_DENIED", "Only super admins can review database indexes", nil)
+	}
+
+	suggestions, err := app.SuggestIndexes(GetDB(c), c.Param("name"))
+	if errors.Is(err, app.ErrNoIndexAdvice) {
+		return fail(c, http.StatusNotFound, "NOT_FOUND", err.Error(), nil)
+	} else if err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to inspect table indexes", err.Error())
+	}
+	return ok(c, suggestions)
+}
+
+// registerMigrationRoutes registers database migration routes
+func registerMigrationRoutes() {
+	webserver.ApiGET("/dbms/migration/plan", GetMigrationPlan)
+	webserver.ApiPOST("/dbms/migration/apply", ApplyMigration)
+	webserver.ApiGET("/dbms/tables/:name/suggest-indexes", SuggestTableIndexes)
+}
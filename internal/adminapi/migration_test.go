@@ -0,0 +1,93 @@
+package adminapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/app"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+)
+
+func TestMigrationPlanAndApply(t *testing.T) {
+	db, e, appCtx := CreateTestAppContext(t)
+
+	call := func(method, path string, handler echo.HandlerFunc, level string) (*httptest.ResponseRecorder, *app.MigrationPlan) {
+		req := httptest.NewRequest(method, "/api/v1/dbms/migration/"+path, nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		c.Set("current_operator", &domain.SysOpr{ID: 1, Username: "tester", Level: level, Status: "enabled"})
+		require.NoError(t, handler(c))
+
+		var response Response
+		_ = json.Unmarshal(rec.Body.Bytes(), &response) //nolint:errcheck
+		dataBytes, _ := json.Marshal(response.Data)     //nolint:errcheck
+		var plan app.MigrationPlan
+		_ = json.Unmarshal(dataBytes, &plan) //nolint:errcheck
+		return rec, &plan
+	}
+
+	// Simulate a deployed schema that predates the remark column
+	require.NoError(t, db.Migrator().DropColumn(&domain.NetNode{}, "remark"))
+
+	rec, plan := call(http.MethodGet, "plan", GetMigrationPlan, "super")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, plan.Pending)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, app.MigrationKindColumn, plan.Changes[0].Kind)
+	assert.Equal(t, "net_node", plan.Changes[0].Table)
+	assert.Equal(t, "remark", plan.Changes[0].Name)
+	assert.False(t, db.Migrator().HasColumn(&domain.NetNode{}, "remark"), "planning must not migrate")
+
+	rec, _ = call(http.MethodPost, "apply", ApplyMigration, "operator")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec, plan = call(http.MethodPost, "apply", ApplyMigration, "super")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, plan.Pending, "unexpected changes: %+v", plan.Changes)
+	assert.True(t, db.Migrator().HasColumn(&domain.NetNode{}, "remark"))
+}
+
+func TestSuggestTableIndexes(t *testing.T) {
+	db, e, appCtx := CreateTestAppContext(t)
+
+	call := func(table string) (*httptest.ResponseRecorder, []app.IndexSuggestion) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/dbms/tables/"+table+"/suggest-indexes", nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		c.SetParamNames("name")
+		c.SetParamValues(table)
+		require.NoError(t, SuggestTableIndexes(c))
+
+		var response Response
+		_ = json.Unmarshal(rec.Body.Bytes(), &response) //nolint:errcheck
+		dataBytes, _ := json.Marshal(response.Data)     //nolint:errcheck
+		var suggestions []app.IndexSuggestion
+		_ = json.Unmarshal(dataBytes, &suggestions) //nolint:errcheck
+		return rec, suggestions
+	}
+
+	// radius_online is filtered by NAS address, which has no index
+	rec, suggestions := call("radius_online")
+	require.Equal(t, http.StatusOK, rec.Code)
+	byColumn := map[string]app.IndexSuggestion{}
+	for _, s := range suggestions {
+		byColumn[s.Column] = s
+	}
+	require.Contains(t, byColumn, "nas_addr")
+	assert.Equal(t, "CREATE INDEX idx_radius_online_nas_addr ON radius_online (nas_addr)", byColumn["nas_addr"].Statement)
+	assert.NotContains(t, byColumn, "username", "indexed columns are not suggested")
+
+	require.NoError(t, db.Exec(byColumn["nas_addr"].Statement).Error)
+	_, suggestions = call("radius_online")
+	for _, s := range suggestions {
+		assert.NotEqual(t, "nas_addr", s.Column)
+	}
+
+	rec, _ = call("sys_config")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
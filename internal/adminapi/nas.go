@@ -4,10 +4,14 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/talkincode/toughradius/v9/internal/app"
 	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors"
 	"github.com/talkincode/toughradius/v9/internal/webserver"
+	"gorm.io/gorm"
 )
 
 // nasPayload represents the NAS device request structure
@@ -24,6 +28,8 @@ type nasPayload struct {
 	Status     string `json:"status" validate:"omitempty,oneof=enabled disabled"`
 	Tags       string `json:"tags" validate:"omitempty,max=200"`
 	Remark     string `json:"remark" validate:"omitempty,max=500"`
+
+	MacAuthDisabled *bool `json:"mac_auth_disabled"`
 }
 
 // nasUpdatePayload relaxes validation rules for partial updates
@@ -40,6 +46,8 @@ type nasUpdatePayload struct {
 	Status     string `json:"status" validate:"omitempty,oneof=enabled disabled"`
 	Tags       string `json:"tags" validate:"omitempty,max=200"`
 	Remark     string `json:"remark" validate:"omitempty,max=500"`
+
+	MacAuthDisabled *bool `json:"mac_auth_disabled"`
 }
 
 // ListNAS retrieves the NAS device list
@@ -129,6 +137,49 @@ func GetNAS(c echo.Context) error {
 	return ok(c, device)
 }
 
+// NasDuplicateGroup is a set of NAS devices sharing one IP address
+type NasDuplicateGroup struct {
+	Ipaddr  string          `json:"ipaddr"`
+	Count   int             `json:"count"`
+	Devices []domain.NetNas `json:"devices"`
+}
+
+// ListDuplicateNAS lists NAS devices whose IP address is used by more than one
+// device, which makes matching RADIUS requests to a NAS ambiguous
+// @Summary list NAS devices with duplicate IP addresses
+// @Tags NAS
+// @Success 200 {array} NasDuplicateGroup
+// @Router /api/v1/network/nas/duplicates [get]
+func ListDuplicateNAS(c echo.Context) error {
+	db := GetDB(c)
+
+	ipaddrs, err := app.DuplicateNasIpaddrs(db)
+	if err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to query duplicate NAS devices", err.Error())
+	}
+
+	groups := make([]NasDuplicateGroup, 0, len(ipaddrs))
+	if len(ipaddrs) == 0 {
+		return ok(c, groups)
+	}
+
+	var devices []domain.NetNas
+	if err := db.Where("ipaddr IN ?", ipaddrs).Order("ipaddr, id").Find(&devices).Error; err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to query duplicate NAS devices", err.Error())
+	}
+
+	for _, device := range devices {
+		if n := len(groups); n == 0 || groups[n-1].Ipaddr != device.Ipaddr {
+			groups = append(groups, NasDuplicateGroup{Ipaddr: device.Ipaddr})
+		}
+		group := &groups[len(groups)-1]
+		group.Devices = append(group.Devices, device)
+		group.Count++
+	}
+
+	return ok(c, groups)
+}
+
 // CreateNAS creates a NAS device
 // @Summary create a NAS device
 // @Tags NAS
@@ -176,8 +227,14 @@ func CreateNAS(c echo.Context) error {
 		Tags:       payload.Tags,
 		Remark:     payload.Remark,
 	}
+	if payload.MacAuthDisabled != nil {
+		device.MacAuthDisabled = *payload.MacAuthDisabled
+	}
 
 	if err := GetDB(c).Create(&device).Error; err != nil {
+		if isUniqueViolation(err) {
+			return fail(c, http.StatusConflict, "IPADDR_EXISTS", "IP address already exists", nil)
+		}
 		return fail(c, http.StatusInternalServerError, "CREATE_FAILED", "Failed to create NAS device", err.Error())
 	}
 
@@ -238,6 +295,9 @@ func UpdateNAS(c echo.Context) error {
 	if payload.CoaPort != nil {
 		device.CoaPort = *payload.CoaPort
 	}
+	if payload.MacAuthDisabled != nil {
+		device.MacAuthDisabled = *payload.MacAuthDisabled
+	}
 	if payload.Model != "" {
 		device.Model = payload.Model
 	}
@@ -258,6 +318,9 @@ func UpdateNAS(c echo.Context) error {
 	}
 
 	if err := GetDB(c).Save(&device).Error; err != nil {
+		if isUniqueViolation(err) {
+			return fail(c, http.StatusConflict, "IPADDR_EXISTS", "IP address already exists", nil)
+		}
 		return fail(c, http.StatusInternalServerError, "UPDATE_FAILED", "Failed to update NAS device", err.Error())
 	}
 
@@ -294,11 +357,130 @@ func DeleteNAS(c echo.Context) error {
 	})
 }
 
+// VendorCapabilitiesResponse describes the device features supported by a vendor
+type VendorCapabilitiesResponse struct {
+	Code         string               `json:"code"`
+	Name         string               `json:"name"`
+	Capabilities vendors.Capabilities `json:"capabilities"`
+}
+
+// GetVendorCapabilities reports which NAS features a vendor supports so the
+// UI can show or hide vendor-specific controls
+// @Summary get vendor capabilities
+// @Tags NAS
+// @Param code path string true "Vendor code"
+// @Success 200 {object} VendorCapabilitiesResponse
+// @Router /api/v1/network/vendors/{code}/capabilities [get]
+func GetVendorCapabilities(c echo.Context) error {
+	code := strings.TrimSpace(c.Param("code"))
+	if code == "" {
+		return fail(c, http.StatusBadRequest, "INVALID_VENDOR", "Vendor code is required", nil)
+	}
+
+	resp := VendorCapabilitiesResponse{
+		Code:         code,
+		Capabilities: vendors.GetCapabilities(code),
+	}
+	if info, found := vendors.Get(code); found {
+		resp.Name = info.Name
+	}
+
+	return ok(c, resp)
+}
+
+const (
+	defaultTopTalkersLimit = 10
+	maxTopTalkersLimit     = 100
+	maxTopTalkersPeriod    = 366 * 24 * time.Hour
+)
+
+// TopTalker is the accounted traffic of one user on a NAS
+type TopTalker struct {
+	Username    string `json:"username"`
+	Sessions    int64  `json:"sessions"`
+	SessionTime int64  `json:"session_time"`
+	InputTotal  int64  `json:"input_total"`
+	OutputTotal int64  `json:"output_total"`
+	Total       int64  `json:"total"`
+}
+
+// GetNASTopTalkers ranks users of a NAS by accounted traffic in a period
+// @Summary get the top talkers of a NAS device
+// @Tags NAS
+// @Param id path int true "NAS ID"
+// @Param start query string false "Period start, a bare date starts at its midnight (defaults to 24 hours before end)"
+// @Param end query string false "Period end, exclusive; a bare date includes that whole day (defaults to now)"
+// @Param limit query int false "Users per page (1-100, default 10)"
+// @Param page query int false "Page number"
+// @Success 200 {array} TopTalker
+// @Router /api/v1/network/nas/{id}/top-talkers [get]
+func GetNASTopTalkers(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_ID", "Invalid NAS ID", nil)
+	}
+
+	var nas domain.NetNas
+	if err := GetDB(c).First(&nas, id).Error; err != nil {
+		return fail(c, http.StatusNotFound, "NOT_FOUND", "NAS device not found", nil)
+	}
+
+	end, err := parseEndTimeInput(c.QueryParam("end"), time.Now())
+	if err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_END_TIME", "Invalid end time", nil)
+	}
+	start, err := parseStartTimeInput(c.QueryParam("start"), end.Add(-24*time.Hour))
+	if err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_START_TIME", "Invalid start time", nil)
+	}
+	if !start.Before(end) || end.Sub(start) > maxTopTalkersPeriod {
+		return fail(c, http.StatusBadRequest, "INVALID_PERIOD", "start must be before end and the period at most 366 days", nil)
+	}
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit < 1 || limit > maxTopTalkersLimit {
+		limit = defaultTopTalkersLimit
+	}
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// Filter on (nas_addr, acct_start_time), covered by idx_radius_accounting_nas_start
+	base := GetRequestDB(c).Model(&domain.RadiusAccounting{}).
+		Where("nas_addr = ? AND acct_start_time >= ? AND acct_start_time < ?", nas.Ipaddr, start, end)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Distinct("username").Count(&total).Error; err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to aggregate accounting records", err.Error())
+	}
+
+	talkers := make([]TopTalker, 0, limit)
+	err = base.Session(&gorm.Session{}).
+		Select("username, COUNT(*) AS sessions, SUM(acct_session_time) AS session_time, " +
+			"SUM(acct_input_total) AS input_total, SUM(acct_output_total) AS output_total, " +
+			"SUM(acct_input_total + acct_output_total) AS total").
+		Group("username").
+		Order("total DESC, username ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Scan(&talkers).Error
+	if err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to aggregate accounting records", err.Error())
+	}
+
+	return paged(c, talkers, total, page, limit)
+}
+
 // registerNASRoutes registers NAS routes
 func registerNASRoutes() {
 	webserver.ApiGET("/network/nas", ListNAS)
+	webserver.ApiGET("/network/nas/duplicates", ListDuplicateNAS)
 	webserver.ApiGET("/network/nas/:id", GetNAS)
 	webserver.ApiPOST("/network/nas", CreateNAS)
 	webserver.ApiPUT("/network/nas/:id", UpdateNAS)
 	webserver.ApiDELETE("/network/nas/:id", DeleteNAS)
+	webserver.ApiGET("/network/nas/:id/top-talkers", GetNASTopTalkers)
+	webserver.ApiGET("/network/nas/:id/dashboard", GetNASDashboard)
+	webserver.ApiGET("/network/vendors/:code/capabilities", GetVendorCapabilities)
 }
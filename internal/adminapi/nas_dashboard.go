@@ -0,0 +1,167 @@
+package adminapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/qos"
+	"github.com/talkincode/toughradius/v9/pkg/metrics"
+	"gorm.io/gorm"
+)
+
+// nasDashboardDeviceTimeout bounds how long the dashboard waits on the device
+var nasDashboardDeviceTimeout = 3 * time.Second
+
+// NasOnlineSummary summarizes the online sessions of a NAS
+type NasOnlineSummary struct {
+	Count      int64      `json:"count"`
+	LastUpdate *time.Time `json:"last_update"`
+}
+
+// NasQoSQueueStats counts the QoS records of a NAS by status
+type NasQoSQueueStats struct {
+	Total   int64 `json:"total"`
+	Pending int64 `json:"pending"`
+	Synced  int64 `json:"synced"`
+	Failed  int64 `json:"failed"`
+}
+
+// NasQoSSummary summarizes the QoS state of a NAS
+type NasQoSSummary struct {
+	QueueStats NasQoSQueueStats  `json:"queue_stats"`
+	Backlog    int64             `json:"backlog"`
+	LastSync   *domain.NasQoSLog `json:"last_sync"`
+}
+
+// NasDashboard aggregates what the NAS detail page shows. Parts read from the
+// device that fell back to stored data are listed in Stale, parts that could
+// not be read at all in Unavailable.
+type NasDashboard struct {
+	Nas         domain.NetNas    `json:"nas"`
+	Online      NasOnlineSummary `json:"online"`
+	QoS         *NasQoSSummary   `json:"qos"`
+	LiveQueues  *qos.LiveQueues  `json:"live_queues"`
+	Stale       []string         `json:"stale"`
+	Unavailable []string         `json:"unavailable"`
+	GeneratedAt time.Time        `json:"generated_at"`
+}
+
+// GetNASDashboard returns the NAS detail page data in a single call
+// @Summary get the dashboard of a NAS device
+// @Tags NAS
+// @Param id path int true "NAS ID"
+// @Success 200 {object} NasDashboard
+// @Router /api/v1/network/nas/{id}/dashboard [get]
+func GetNASDashboard(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_ID", "Invalid NAS ID", nil)
+	}
+
+	db := GetRequestDB(c)
+	var nas domain.NetNas
+	if err := db.First(&nas, id).Error; err != nil {
+		return fail(c, http.StatusNotFound, "NOT_FOUND", "NAS device not found", nil)
+	}
+	nas.Secret = ""
+	nas.APIPassword = ""
+
+	dashboard := NasDashboard{
+		Nas:         nas,
+		Stale:       []string{},
+		Unavailable: []string{},
+		GeneratedAt: time.Now(),
+	}
+
+	if err := db.Model(&domain.RadiusOnline{}).Where("nas_addr = ?", nas.Ipaddr).Count(&dashboard.Online.Count).Error; err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to count online sessions", err.Error())
+	}
+	if dashboard.Online.Count > 0 {
+		var latest domain.RadiusOnline
+		if err := db.Select("last_update").Where("nas_addr = ?", nas.Ipaddr).Order("last_update DESC").First(&latest).Error; err == nil {
+			dashboard.Online.LastUpdate = &latest.LastUpdate
+		}
+	}
+
+	if !nas.QoSEnabled {
+		return ok(c, dashboard)
+	}
+
+	dashboard.QoS, err = nasQoSSummary(db, nas.ID)
+	if err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to query QoS status", err.Error())
+	}
+
+	qosService, isValidType := GetAppContext(c).GetQoSService().(*qos.NasQoSService)
+	if !isValidType || qosService == nil {
+		dashboard.Unavailable = append(dashboard.Unavailable, "live_queues")
+		return ok(c, dashboard)
+	}
+
+	// The device client may not honor the context, so wait on it separately
+	ctx, cancel := context.WithTimeout(c.Request().Context(), nasDashboardDeviceTimeout)
+	defer cancel()
+	liveCh := make(chan *qos.LiveQueues, 1)
+	go func() {
+		liveCh <- qosService.GetLiveQueues(ctx, &nas)
+	}()
+
+	select {
+	case live := <-liveCh:
+		dashboard.LiveQueues = live
+		if live.Stale {
+			dashboard.Stale = append(dashboard.Stale, "live_queues")
+		}
+	case <-ctx.Done():
+		dashboard.Unavailable = append(dashboard.Unavailable, "live_queues")
+	}
+
+	return ok(c, dashboard)
+}
+
+// nasQoSSummary reads the stored QoS state of a NAS
+func nasQoSSummary(db *gorm.DB, nasID int64) (*NasQoSSummary, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := db.Model(&domain.NasQoS{}).
+		Select("status, COUNT(*) AS count").
+		Where("nas_id = ?", nasID).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	summary := &NasQoSSummary{}
+	for _, row := range rows {
+		summary.QueueStats.Total += row.Count
+		switch row.Status {
+		case "pending":
+			summary.QueueStats.Pending = row.Count
+		case "synced":
+			summary.QueueStats.Synced = row.Count
+		case "failed":
+			summary.QueueStats.Failed = row.Count
+		}
+	}
+	if store := metrics.GetStore(); store != nil {
+		summary.Backlog = store.GetGaugeValue(qos.MetricsQoSBacklogNasPrefix + strconv.FormatInt(nasID, 10))
+	}
+
+	var lastLog domain.NasQoSLog
+	err := db.Where("nas_id = ?", nasID).Order("executed_at DESC").First(&lastLog).Error
+	switch {
+	case err == nil:
+		summary.LastSync = &lastLog
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,128 @@
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/qos/clients"
+)
+
+// slowQoSClient blocks ListQueues like an unresponsive device
+type slowQoSClient struct {
+	fakeQoSClient
+	release chan struct{}
+}
+
+func (s *slowQoSClient) ListQueues(_ context.Context) ([]clients.QueueEntry, error) {
+	<-s.release
+	return nil, nil
+}
+
+func TestGetNASDashboard(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx, client := setupQoSTestApp(t, db)
+	nas := createTestQoSNas(t, db, "192.168.9.5")
+	nasID := strconv.FormatInt(nas.ID, 10)
+
+	now := time.Now()
+	for i, sessionID := range []string{"s1", "s2"} {
+		require.NoError(t, db.Create(&domain.RadiusOnline{
+			ID: int64(i + 1), Username: "user" + sessionID, NasAddr: nas.Ipaddr, AcctSessionId: sessionID,
+			AcctStartTime: now, LastUpdate: now.Add(-time.Duration(i) * time.Minute),
+		}).Error)
+	}
+	for i, status := range []string{"synced", "synced", "pending", "failed"} {
+		require.NoError(t, db.Create(&domain.NasQoS{
+			ID: int64(400 + i), UserID: int64(i), NasID: nas.ID, QoSName: "user_" + strconv.Itoa(i),
+			UpRate: 1024, DownRate: 1024, Status: status,
+		}).Error)
+	}
+	require.NoError(t, db.Create(&domain.NasQoSLog{
+		ID: 1, NasID: nas.ID, Action: "synced", Status: "success", ExecutedAt: now,
+	}).Error)
+	client.queues = []clients.QueueEntry{{ID: "*1", Name: "user_0"}}
+
+	call := func(t *testing.T, id string) NasDashboard {
+		e := setupTestEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/network/nas/"+id+"/dashboard", nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, GetNASDashboard(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		dataBytes, _ := json.Marshal(response.Data)
+		var dashboard NasDashboard
+		require.NoError(t, json.Unmarshal(dataBytes, &dashboard))
+		return dashboard
+	}
+
+	t.Run("All sources available", func(t *testing.T) {
+		dashboard := call(t, nasID)
+		assert.Empty(t, dashboard.Nas.Secret)
+		assert.Equal(t, int64(2), dashboard.Online.Count)
+		require.NotNil(t, dashboard.Online.LastUpdate)
+		assert.WithinDuration(t, now, *dashboard.Online.LastUpdate, time.Second)
+
+		require.NotNil(t, dashboard.QoS)
+		assert.Equal(t, NasQoSQueueStats{Total: 4, Pending: 1, Synced: 2, Failed: 1}, dashboard.QoS.QueueStats)
+		require.NotNil(t, dashboard.QoS.LastSync)
+		assert.Equal(t, "synced", dashboard.QoS.LastSync.Action)
+
+		require.NotNil(t, dashboard.LiveQueues)
+		assert.Equal(t, "live", dashboard.LiveQueues.Source)
+		assert.Empty(t, dashboard.Stale)
+		assert.Empty(t, dashboard.Unavailable)
+	})
+
+	t.Run("Unreachable device is served from snapshot", func(t *testing.T) {
+		appCtx.qosService.SetClientFactory(func(_ context.Context, _ *domain.NetNas) (clients.QoSClient, error) {
+			return nil, errors.New("connection refused")
+		})
+		other := createTestQoSNas(t, db, "192.168.9.6")
+
+		dashboard := call(t, strconv.FormatInt(other.ID, 10))
+		assert.Equal(t, int64(0), dashboard.Online.Count)
+		assert.Nil(t, dashboard.Online.LastUpdate)
+		require.NotNil(t, dashboard.LiveQueues)
+		assert.Equal(t, "snapshot", dashboard.LiveQueues.Source)
+		assert.Equal(t, []string{"live_queues"}, dashboard.Stale)
+	})
+
+	t.Run("Hanging device times out", func(t *testing.T) {
+		slow := &slowQoSClient{release: make(chan struct{})}
+		defer close(slow.release)
+		appCtx.qosService.SetClientFactory(func(_ context.Context, _ *domain.NetNas) (clients.QoSClient, error) {
+			return slow, nil
+		})
+		timeout := nasDashboardDeviceTimeout
+		nasDashboardDeviceTimeout = 50 * time.Millisecond
+		defer func() { nasDashboardDeviceTimeout = timeout }()
+		other := createTestQoSNas(t, db, "192.168.9.7")
+
+		dashboard := call(t, strconv.FormatInt(other.ID, 10))
+		assert.NotNil(t, dashboard.QoS)
+		assert.Nil(t, dashboard.LiveQueues)
+		assert.Equal(t, []string{"live_queues"}, dashboard.Unavailable)
+	})
+
+	t.Run("QoS disabled", func(t *testing.T) {
+		plain := createTestNas(db, "plain-nas", "192.168.9.8")
+		dashboard := call(t, strconv.FormatInt(plain.ID, 10))
+		assert.Nil(t, dashboard.QoS)
+		assert.Nil(t, dashboard.LiveQueues)
+		assert.Empty(t, dashboard.Unavailable)
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors"
 	"gorm.io/gorm"
 )
 
@@ -213,6 +214,49 @@ func TestGetNAS(t *testing.T) {
 	}
 }
 
+func TestGetVendorCapabilities(t *testing.T) {
+	db, e, appCtx := CreateTestAppContext(t)
+
+	tests := []struct {
+		name     string
+		code     string
+		expected vendors.Capabilities
+	}{
+		{
+			name:     "Mikrotik supports QoS, live queues and CoA",
+			code:     vendors.CodeMikrotik,
+			expected: vendors.Capabilities{QoS: true, FetchServices: true, RateLimitCoA: true},
+		},
+		{
+			name:     "Generic vendor supports nothing",
+			code:     vendors.CodeStandard,
+			expected: vendors.Capabilities{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/network/vendors/"+tt.code+"/capabilities", nil)
+			rec := httptest.NewRecorder()
+			c := CreateTestContext(e, db, req, rec, appCtx)
+			c.SetParamNames("code")
+			c.SetParamValues(tt.code)
+
+			require.NoError(t, GetVendorCapabilities(c))
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var response Response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			dataBytes, _ := json.Marshal(response.Data)
+			var result VendorCapabilitiesResponse
+			require.NoError(t, json.Unmarshal(dataBytes, &result))
+
+			assert.Equal(t, tt.code, result.Code)
+			assert.Equal(t, tt.expected, result.Capabilities)
+		})
+	}
+}
+
 func TestCreateNAS(t *testing.T) {
 	db, e, appCtx := CreateTestAppContext(t)
 
@@ -710,3 +754,127 @@ func TestNASEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestGetNASTopTalkers(t *testing.T) {
+	db, e, appCtx := CreateTestAppContext(t)
+	nas := createTestNas(db, "talker-nas", "192.168.50.1")
+	nasID := strconv.FormatInt(nas.ID, 10)
+
+	now := time.Now()
+	records := []domain.RadiusAccounting{
+		{ID: 1, Username: "alice", AcctSessionId: "a1", NasAddr: nas.Ipaddr, AcctInputTotal: 100, AcctOutputTotal: 900, AcctSessionTime: 60, AcctStartTime: now.Add(-2 * time.Hour)},
+		{ID: 2, Username: "alice", AcctSessionId: "a2", NasAddr: nas.Ipaddr, AcctInputTotal: 50, AcctOutputTotal: 450, AcctSessionTime: 30, AcctStartTime: now.Add(-time.Hour)},
+		{ID: 3, Username: "bob", AcctSessionId: "b1", NasAddr: nas.Ipaddr, AcctInputTotal: 2000, AcctOutputTotal: 3000, AcctStartTime: now.Add(-3 * time.Hour)},
+		{ID: 4, Username: "carol", AcctSessionId: "c1", NasAddr: nas.Ipaddr, AcctInputTotal: 10, AcctOutputTotal: 10, AcctStartTime: now.Add(-time.Hour)},
+		// Outside the default period
+		{ID: 5, Username: "carol", AcctSessionId: "c0", NasAddr: nas.Ipaddr, AcctInputTotal: 99999, AcctOutputTotal: 99999, AcctStartTime: now.Add(-48 * time.Hour)},
+		// Another NAS
+		{ID: 6, Username: "dave", AcctSessionId: "d1", NasAddr: "192.168.50.2", AcctInputTotal: 99999, AcctOutputTotal: 99999, AcctStartTime: now.Add(-time.Hour)},
+	}
+	require.NoError(t, db.Create(&records).Error)
+
+	call := func(t *testing.T, query string) (*httptest.ResponseRecorder, []TopTalker, *Meta) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/network/nas/"+nasID+"/top-talkers"+query, nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		c.SetParamNames("id")
+		c.SetParamValues(nasID)
+		require.NoError(t, GetNASTopTalkers(c))
+		if rec.Code != http.StatusOK {
+			return rec, nil, nil
+		}
+
+		var response Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		dataBytes, _ := json.Marshal(response.Data)
+		var talkers []TopTalker
+		require.NoError(t, json.Unmarshal(dataBytes, &talkers))
+		return rec, talkers, response.Meta
+	}
+
+	t.Run("Ordered by total traffic", func(t *testing.T) {
+		_, talkers, meta := call(t, "")
+		require.Len(t, talkers, 3)
+		assert.Equal(t, int64(3), meta.Total)
+
+		assert.Equal(t, "bob", talkers[0].Username)
+		assert.Equal(t, int64(5000), talkers[0].Total)
+
+		assert.Equal(t, "alice", talkers[1].Username)
+		assert.Equal(t, int64(2), talkers[1].Sessions)
+		assert.Equal(t, int64(150), talkers[1].InputTotal)
+		assert.Equal(t, int64(1350), talkers[1].OutputTotal)
+		assert.Equal(t, int64(1500), talkers[1].Total)
+		assert.Equal(t, int64(90), talkers[1].SessionTime)
+
+		assert.Equal(t, "carol", talkers[2].Username)
+		assert.Equal(t, int64(20), talkers[2].Total)
+	})
+
+	t.Run("Limit and page", func(t *testing.T) {
+		_, talkers, meta := call(t, "?limit=1&page=2")
+		require.Len(t, talkers, 1)
+		assert.Equal(t, "alice", talkers[0].Username)
+		assert.Equal(t, int64(3), meta.Total)
+	})
+
+	t.Run("Invalid period", func(t *testing.T) {
+		rec, _, _ := call(t, "?start=2026-02-01&end=2026-01-01")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Date only period", func(t *testing.T) {
+		require.NoError(t, db.Create(&domain.RadiusAccounting{
+			ID: 7, Username: "erin", AcctSessionId: "e1", NasAddr: nas.Ipaddr, AcctInputTotal: 5, AcctOutputTotal: 5,
+			AcctStartTime: time.Date(2026, 10, 1, 10, 0, 0, 0, time.Local),
+		}).Error)
+
+		// A bare start date begins at midnight
+		_, talkers, _ := call(t, "?start=2026-10-01&end=2026-10-02")
+		require.Len(t, talkers, 1)
+		assert.Equal(t, "erin", talkers[0].Username)
+
+		// A bare end date includes that whole day
+		rec, talkers, _ := call(t, "?start=2026-10-01&end=2026-10-01")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Len(t, talkers, 1)
+		assert.Equal(t, "erin", talkers[0].Username)
+
+		_, talkers, _ = call(t, "?start=2026-10-02&end=2026-10-02")
+		assert.Empty(t, talkers)
+	})
+}
+
+func TestListDuplicateNAS(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx := setupTestApp(t, db)
+
+	call := func() []NasDuplicateGroup {
+		e := setupTestEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/network/nas/duplicates", nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		require.NoError(t, ListDuplicateNAS(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		dataBytes, _ := json.Marshal(response.Data)
+		var groups []NasDuplicateGroup
+		require.NoError(t, json.Unmarshal(dataBytes, &groups))
+		return groups
+	}
+
+	createTestNas(db, "unique", "10.1.0.1")
+	assert.Empty(t, call())
+
+	createTestNas(db, "dup-a", "10.1.0.2")
+	createTestNas(db, "dup-b", "10.1.0.2")
+	createTestNas(db, "dup-c", "10.1.0.2")
+
+	groups := call()
+	require.Len(t, groups, 1)
+	assert.Equal(t, "10.1.0.2", groups[0].Ipaddr)
+	assert.Equal(t, 3, groups[0].Count)
+	assert.Len(t, groups[0].Devices, 3)
+}
@@ -13,6 +13,18 @@ import (
 	"github.com/talkincode/toughradius/v9/internal/webserver"
 )
 
+// nodeListOptions defines pagination and sortable fields for network nodes
+var nodeListOptions = listOptions{
+	SortFields: map[string]bool{
+		"id":         true,
+		"name":       true,
+		"created_at": true,
+		"updated_at": true,
+	},
+	DefaultSort:  "id",
+	DefaultOrder: "DESC",
+}
+
 // nodePayload defines the network node request structure
 type nodePayload struct {
 	Name   string `json:"name" validate:"required,min=1,max=100"`
@@ -37,26 +49,15 @@ func registerNodesRoutes() {
 
 // listNodes retrieves the network node list
 func listNodes(c echo.Context) error {
-	page, pageSize := parsePagination(c)
-
 	base := GetDB(c).Model(&domain.NetNode{})
 	base = applyNodeFilters(base, c)
 
-	var total int64
-	if err := base.Count(&total).Error; err != nil {
-		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to query network nodes", err.Error())
-	}
-
-	var nodes []domain.NetNode
-	if err := base.
-		Order("id DESC").
-		Offset((page - 1) * pageSize).
-		Limit(pageSize).
-		Find(&nodes).Error; err != nil {
+	result, err := listQuery[domain.NetNode](c, base, nodeListOptions)
+	if err != nil {
 		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to query network nodes", err.Error())
 	}
 
-	return paged(c, nodes, total, page, pageSize)
+	return paged(c, result.Items, result.Total, result.Page, result.PageSize)
 }
 
 // getNode retrieves a single network node
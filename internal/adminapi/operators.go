@@ -114,6 +114,21 @@ func updateCurrentOperator(c echo.Context) error {
 	return ok(c, currentOpr)
 }
 
+// operatorListOptions defines pagination and sortable fields for operators
+var operatorListOptions = listOptions{
+	SortFields: map[string]bool{
+		"id":         true,
+		"username":   true,
+		"realname":   true,
+		"level":      true,
+		"status":     true,
+		"last_login": true,
+		"created_at": true,
+	},
+	DefaultSort:  "id",
+	DefaultOrder: "DESC",
+}
+
 // List operators（Only super admin and admin can access）
 func listOperators(c echo.Context) error {
 	currentOpr, err := resolveOperatorFromContext(c)
@@ -126,31 +141,21 @@ func listOperators(c echo.Context) error {
 		return fail(c, http.StatusForbidden, "PERMISSION_DENIED", "No permission to access operator list", nil)
 	}
 
-	page, pageSize := parsePagination(c)
-
 	base := GetDB(c).Model(&domain.SysOpr{})
 	base = applyOperatorFilters(base, c)
 
-	var total int64
-	if err := base.Count(&total).Error; err != nil {
-		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to query operators", err.Error())
-	}
-
-	var operators []domain.SysOpr
-	if err := base.
-		Order("id DESC").
-		Offset((page - 1) * pageSize).
-		Limit(pageSize).
-		Find(&operators).Error; err != nil {
+	result, err := listQuery[domain.SysOpr](c, base, operatorListOptions)
+	if err != nil {
 		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to query operators", err.Error())
 	}
+	operators := result.Items
 
 	// Mask password
 	for i := range operators {
 		operators[i].Password = ""
 	}
 
-	return paged(c, operators, total, page, pageSize)
+	return paged(c, operators, result.Total, result.Page, result.PageSize)
 }
 
 // Get a single operator (only super admins and admins can access)
@@ -10,6 +10,27 @@ import (
 	"github.com/talkincode/toughradius/v9/internal/webserver"
 )
 
+// profileListOptions defines pagination and sortable fields for RADIUS profiles
+var profileListOptions = listOptions{
+	SortFields: map[string]bool{
+		"id":         true,
+		"name":       true,
+		"status":     true,
+		"active_num": true,
+		"up_rate":    true,
+		"down_rate":  true,
+		"addr_pool":  true,
+		"domain":     true,
+		"created_at": true,
+		"updated_at": true,
+	},
+	DefaultSort:     "id",
+	DefaultOrder:    "DESC",
+	PageSizeParam:   "perPage",
+	DefaultPageSize: 10,
+	MaxPageSize:     100,
+}
+
 // ListProfiles retrieves the RADIUS profile list
 // @Summary get the RADIUS profile list
 // @Tags RadiusProfile
@@ -22,27 +43,6 @@ import (
 func ListProfiles(c echo.Context) error {
 	db := GetDB(c)
 
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	perPage, _ := strconv.Atoi(c.QueryParam("perPage"))
-	if page < 1 {
-		page = 1
-	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 10
-	}
-
-	sortField := c.QueryParam("sort")
-	order := c.QueryParam("order")
-	if sortField == "" {
-		sortField = "id"
-	}
-	if order != "ASC" && order != "DESC" {
-		order = "DESC"
-	}
-
-	var total int64
-	var profiles []domain.RadiusProfile
-
 	query := db.Model(&domain.RadiusProfile{})
 
 	// Support filtering by name (case-insensitive)
@@ -77,12 +77,12 @@ func ListProfiles(c echo.Context) error {
 		}
 	}
 
-	query.Count(&total)
-
-	offset := (page - 1) * perPage
-	query.Order(sortField + " " + order).Limit(perPage).Offset(offset).Find(&profiles)
+	result, err := listQuery[domain.RadiusProfile](c, query, profileListOptions)
+	if err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to query profiles", err.Error())
+	}
 
-	return paged(c, profiles, total, page, perPage)
+	return paged(c, result.Items, result.Total, result.Page, result.PageSize)
 }
 
 // GetProfile retrieves a single RADIUS profile
@@ -430,7 +430,7 @@ func DeleteProfile(c echo.Context) error {
 func registerProfileRoutes() {
 	webserver.ApiGET("/radius-profiles", ListProfiles)
 	webserver.ApiGET("/radius-profiles/:id", GetProfile)
-	webserver.ApiPOST("/radius-profiles", CreateProfile)
-	webserver.ApiPUT("/radius-profiles/:id", UpdateProfile)
-	webserver.ApiDELETE("/radius-profiles/:id", DeleteProfile)
+	webserver.ApiPOST("/radius-profiles", CreateProfile, invalidatesCache(cacheDashboardStats))
+	webserver.ApiPUT("/radius-profiles/:id", UpdateProfile, invalidatesCache(cacheDashboardStats))
+	webserver.ApiDELETE("/radius-profiles/:id", DeleteProfile, invalidatesCache(cacheDashboardStats))
 }
@@ -2,6 +2,7 @@ package adminapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -21,17 +22,17 @@ type qosSyncRequest struct {
 
 // qosSyncResponse represents sync result
 type qosSyncResponse struct {
-	NasID         int64     `json:"nas_id,string"`
-	NasAddr       string    `json:"nas_addr"`
-	VendorCode    string    `json:"vendor_code"`
-	QoSEnabled    bool      `json:"qos_enabled"`
-	TotalQueue    int64     `json:"total_queue"`
-	PendingQueue  int64     `json:"pending_queue"`
-	ProcessedCount int64    `json:"processed_count"`
-	Message       string    `json:"message"`
-	StartTime     time.Time `json:"start_time"`
-	EndTime       time.Time `json:"end_time"`
-	Duration      string    `json:"duration"`
+	NasID          int64     `json:"nas_id,string"`
+	NasAddr        string    `json:"nas_addr"`
+	VendorCode     string    `json:"vendor_code"`
+	QoSEnabled     bool      `json:"qos_enabled"`
+	TotalQueue     int64     `json:"total_queue"`
+	PendingQueue   int64     `json:"pending_queue"`
+	ProcessedCount int64     `json:"processed_count"`
+	Message        string    `json:"message"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	Duration       string    `json:"duration"`
 }
 
 // ManualTriggerQoSSync manually triggers QoS polling for a specific NAS device
@@ -73,6 +74,10 @@ func ManualTriggerQoSSync(c echo.Context) error {
 	if !isValidType {
 		return fail(c, http.StatusInternalServerError, "SERVICE_ERROR", "Invalid QoS service type", nil)
 	}
+	if qosService.SyncPaused() {
+		zap.L().Info("Manual QoS sync skipped, QoS sync is paused", zap.Int64("nas_id", nasID))
+		return fail(c, http.StatusConflict, "QOS_SYNC_PAUSED", "QoS sync is paused", nil)
+	}
 
 	startTime := time.Now()
 
@@ -121,17 +126,17 @@ func ManualTriggerQoSSync(c echo.Context) error {
 	)
 
 	response := qosSyncResponse{
-		NasID:         nasID,
-		NasAddr:       nas.Ipaddr,
-		VendorCode:    nas.VendorCode,
-		QoSEnabled:    nas.QoSEnabled,
-		TotalQueue:    totalQueue,
-		PendingQueue:  pendingQueue,
+		NasID:          nasID,
+		NasAddr:        nas.Ipaddr,
+		VendorCode:     nas.VendorCode,
+		QoSEnabled:     nas.QoSEnabled,
+		TotalQueue:     totalQueue,
+		PendingQueue:   pendingQueue,
 		ProcessedCount: processedCount,
-		Message:       fmt.Sprintf("Manually synced %d QoS queues", processedCount),
-		StartTime:     startTime,
-		EndTime:       endTime,
-		Duration:      duration.String(),
+		Message:        fmt.Sprintf("Manually synced %d QoS queues", processedCount),
+		StartTime:      startTime,
+		EndTime:        endTime,
+		Duration:       duration.String(),
 	}
 
 	return ok(c, response)
@@ -170,13 +175,13 @@ func GetQoSStatus(c echo.Context) error {
 	db.Where("nas_id = ?", nasID).Order("executed_at DESC").First(&lastLog)
 
 	status := map[string]interface{}{
-		"nas_id":        nasID,
-		"nas_addr":      nas.Ipaddr,
-		"vendor_code":   nas.VendorCode,
-		"qos_enabled":   nas.QoSEnabled,
-		"qos_method":    nas.QoSMethod,
-		"api_host":      nas.APIHost,
-		"api_port":      nas.APIPort,
+		"nas_id":      nasID,
+		"nas_addr":    nas.Ipaddr,
+		"vendor_code": nas.VendorCode,
+		"qos_enabled": nas.QoSEnabled,
+		"qos_method":  nas.QoSMethod,
+		"api_host":    nas.APIHost,
+		"api_port":    nas.APIPort,
 		"queue_stats": map[string]interface{}{
 			"total":   totalQueue,
 			"pending": pendingQueue,
@@ -184,10 +189,10 @@ func GetQoSStatus(c echo.Context) error {
 			"failed":  failedQueue,
 		},
 		"last_sync": map[string]interface{}{
-			"action":       lastLog.Action,
-			"status":       lastLog.Status,
-			"error_msg":    lastLog.ErrorMsg,
-			"executed_at":  lastLog.ExecutedAt,
+			"action":      lastLog.Action,
+			"status":      lastLog.Status,
+			"error_msg":   lastLog.ErrorMsg,
+			"executed_at": lastLog.ExecutedAt,
 		},
 	}
 
@@ -202,7 +207,7 @@ func GetQoSStatus(c echo.Context) error {
 // @Param status query string false "Filter by status (pending, synced, failed)"
 // @Param page query int false "Page number"
 // @Param perPage query int false "Items per page"
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} PageResult
 // @Router /api/v1/network/nas/{id}/qos/queues [get]
 func ListQoSQueues(c echo.Context) error {
 	nasID, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -232,27 +237,327 @@ func ListQoSQueues(c echo.Context) error {
 	var total int64
 	var queues []domain.NasQoS
 
-	query := db.Where("nas_id = ?", nasID)
+	query := db.Model(&domain.NasQoS{}).Where("nas_id = ?", nasID)
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
 
-	query.Count(&total)
+	if err := query.Count(&total).Error; err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to count QoS queues", err.Error())
+	}
 
 	offset := (page - 1) * perPage
-	query.Order("id DESC").Limit(perPage).Offset(offset).Find(&queues)
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"data":  queues,
-		"total": total,
-		"page":  page,
-		"perPage": perPage,
-	})
+	if err := query.Order("id DESC").Limit(perPage).Offset(offset).Find(&queues).Error; err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to query QoS queues", err.Error())
+	}
+
+	return pageResult(c, queues, total, page, perPage)
+}
+
+// SyncSingleQoSQueue retries one QoS queue immediately
+//
+// @Summary sync a single QoS queue for a NAS device
+// @Tags QoS
+// @Param id path int true "NAS ID"
+// @Param qid path int true "QoS queue ID"
+// @Success 200 {object} domain.NasQoS
+// @Router /api/v1/network/nas/{id}/qos/queues/{qid}/sync [post]
+func SyncSingleQoSQueue(c echo.Context) error {
+	nasID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_ID", "Invalid NAS ID", nil)
+	}
+	queueID, err := strconv.ParseInt(c.Param("qid"), 10, 64)
+	if err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_ID", "Invalid QoS queue ID", nil)
+	}
+
+	var queue domain.NasQoS
+	if err := GetDB(c).Where("id = ? AND nas_id = ?", queueID, nasID).First(&queue).Error; err != nil {
+		return fail(c, http.StatusNotFound, "NOT_FOUND", "QoS queue not found", nil)
+	}
+
+	qosService, isValidType := GetAppContext(c).GetQoSService().(*qos.NasQoSService)
+	if !isValidType || qosService == nil {
+		return fail(c, http.StatusInternalServerError, "SERVICE_ERROR", "QoS service not initialized", nil)
+	}
+	if qosService.SyncPaused() {
+		zap.L().Info("Manual QoS queue sync skipped, QoS sync is paused", zap.Int64("qos_id", queueID))
+		return fail(c, http.StatusConflict, "QOS_SYNC_PAUSED", "QoS sync is paused", nil)
+	}
+
+	result, err := qosService.SyncQueueNow(c.Request().Context(), queueID)
+	if err != nil {
+		return fail(c, http.StatusInternalServerError, "SYNC_ERROR", "Failed to sync QoS queue", err.Error())
+	}
+
+	zap.L().Info("Manual QoS queue sync triggered",
+		zap.Int64("nas_id", nasID),
+		zap.Int64("qos_id", queueID),
+		zap.String("status", result.Status),
+	)
+
+	return ok(c, result)
+}
+
+// PlanQoSQueue previews what syncing a QoS queue would change on its NAS
+// device by comparing the queue read from the device with the desired
+// configuration. Nothing is written to the device or the database.
+//
+// @Summary preview the device changes of a QoS queue sync
+// @Tags QoS
+// @Param id path int true "NAS ID"
+// @Param qid path int true "QoS queue ID"
+// @Success 200 {object} qos.QueuePlan
+// @Router /api/v1/network/nas/{id}/qos/queues/{qid}/plan [post]
+func PlanQoSQueue(c echo.Context) error {
+	nasID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_ID", "Invalid NAS ID", nil)
+	}
+	queueID, err := strconv.ParseInt(c.Param("qid"), 10, 64)
+	if err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_ID", "Invalid QoS queue ID", nil)
+	}
+
+	db := GetDB(c)
+	var nas domain.NetNas
+	if err := db.First(&nas, nasID).Error; err != nil {
+		return fail(c, http.StatusNotFound, "NOT_FOUND", "NAS device not found", nil)
+	}
+	if !nas.QoSEnabled {
+		return fail(c, http.StatusBadRequest, "QOS_DISABLED", "QoS is not enabled for this NAS device", nil)
+	}
+
+	var queue domain.NasQoS
+	if err := db.Where("id = ? AND nas_id = ?", queueID, nasID).First(&queue).Error; err != nil {
+		return fail(c, http.StatusNotFound, "NOT_FOUND", "QoS queue not found", nil)
+	}
+
+	qosService, isValidType := GetAppContext(c).GetQoSService().(*qos.NasQoSService)
+	if !isValidType || qosService == nil {
+		return fail(c, http.StatusInternalServerError, "SERVICE_ERROR", "QoS service not initialized", nil)
+	}
+
+	plan, err := qosService.PlanQueue(c.Request().Context(), &queue)
+	if err != nil {
+		return fail(c, http.StatusBadGateway, "DEVICE_ERROR", "Failed to read QoS queue from device", err.Error())
+	}
+
+	return ok(c, plan)
+}
+
+// qosHistoryEntry is one QoS sync log entry of a user with its queue and NAS
+type qosHistoryEntry struct {
+	ID             int64     `json:"id,string"`
+	QoSID          int64     `json:"qos_id,string"`
+	NasID          int64     `json:"nas_id,string"`
+	NasName        string    `json:"nas_name"`
+	NasAddr        string    `json:"nas_addr"`
+	QoSName        string    `json:"qos_name"`
+	QueueStatus    string    `json:"queue_status"` // Current status of the queue, empty once deleted
+	UpRate         int       `json:"up_rate"`
+	DownRate       int       `json:"down_rate"`
+	Action         string    `json:"action"`
+	Status         string    `json:"status"`
+	ErrorMsg       string    `json:"error_msg"`
+	RequestPayload string    `json:"request_payload"`
+	ExecutedAt     time.Time `json:"executed_at"`
+}
+
+// ListUserQoSHistory lists the QoS sync history of a user across all NAS
+// devices, newest first
+//
+// @Summary list the QoS provisioning history of a user
+// @Tags QoS
+// @Param id path int true "User ID"
+// @Param nas_id query int false "Filter by NAS ID"
+// @Param status query string false "Filter by log status (success, failure)"
+// @Param action query string false "Filter by action"
+// @Param page query int false "Page number"
+// @Param perPage query int false "Items per page"
+// @Success 200 {object} PageResult
+// @Router /api/v1/radius/users/{id}/qos-history [get]
+func ListUserQoSHistory(c echo.Context) error {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_ID", "Invalid user ID", nil)
+	}
+
+	db := GetRequestDB(c)
+	var user domain.RadiusUser
+	if err := db.Select("id").First(&user, userID).Error; err != nil {
+		return fail(c, http.StatusNotFound, "USER_NOT_FOUND", "User not found", nil)
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	perPage, _ := strconv.Atoi(c.QueryParam("perPage"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	// Logs outlive their queue, so the queue and NAS are optional
+	query := db.Table("nas_qos_log AS l").
+		Joins("LEFT JOIN nas_qos AS q ON q.id = l.qo_s_id").
+		Joins("LEFT JOIN net_nas AS n ON n.id = l.nas_id").
+		Where("l.user_id = ?", userID)
+	if nasID := c.QueryParam("nas_id"); nasID != "" {
+		query = query.Where("l.nas_id = ?", nasID)
+	}
+	if status := c.QueryParam("status"); status != "" {
+		query = query.Where("l.status = ?", status)
+	}
+	if action := c.QueryParam("action"); action != "" {
+		query = query.Where("l.action = ?", action)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to count QoS history", err.Error())
+	}
+
+	entries := []qosHistoryEntry{}
+	if err := query.Select("l.id, l.qo_s_id, l.nas_id, n.name AS nas_name, n.ipaddr AS nas_addr, " +
+		"q.qo_s_name, q.status AS queue_status, q.up_rate, q.down_rate, " +
+		"l.action, l.status, l.error_msg, l.request_payload, l.executed_at").
+		Order("l.executed_at DESC, l.id DESC").
+		Limit(perPage).
+		Offset((page - 1) * perPage).
+		Scan(&entries).Error; err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to query QoS history", err.Error())
+	}
+
+	return pageResult(c, entries, total, page, perPage)
+}
+
+// RetryFailedQoS re-queues the failed QoS records of all NAS devices with
+// their retry counters reset, e.g. after a network-wide outage
+//
+// @Summary re-queue failed QoS records across all NAS devices
+// @Tags QoS
+// @Param limit query int false "Maximum records to re-queue (default and max 5000)"
+// @Success 200 {object} qos.RequeueResult
+// @Router /api/v1/network/qos/retry-failed [post]
+func RetryFailedQoS(c echo.Context) error {
+	limit := qos.MaxRequeueFailed
+	if value := c.QueryParam("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > qos.MaxRequeueFailed {
+			return fail(c, http.StatusBadRequest, "INVALID_LIMIT", fmt.Sprintf("limit must be between 1 and %d", qos.MaxRequeueFailed), nil)
+		}
+		limit = parsed
+	}
+
+	qosService, isValidType := GetAppContext(c).GetQoSService().(*qos.NasQoSService)
+	if !isValidType || qosService == nil {
+		return fail(c, http.StatusInternalServerError, "SERVICE_ERROR", "QoS service not initialized", nil)
+	}
+
+	result, err := qosService.RequeueFailed(c.Request().Context(), limit)
+	if err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to re-queue failed QoS records", err.Error())
+	}
+
+	zap.L().Info("Failed QoS records re-queued",
+		zap.Int64("requeued", result.Requeued),
+		zap.Int("nas_count", len(result.Nas)),
+		zap.Int64("remaining", result.Remaining),
+	)
+
+	return ok(c, result)
+}
+
+// ListLiveQueues reads the queue list directly from a NAS device
+//
+// @Summary list queues currently on a NAS device
+// @Tags QoS
+// @Param id path int true "NAS ID"
+// @Success 200 {object} qos.LiveQueues
+// @Router /api/v1/network/nas/{id}/queues/live [get]
+func ListLiveQueues(c echo.Context) error {
+	nasID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_ID", "Invalid NAS ID", nil)
+	}
+
+	var nas domain.NetNas
+	if err := GetDB(c).First(&nas, nasID).Error; err != nil {
+		return fail(c, http.StatusNotFound, "NOT_FOUND", "NAS device not found", nil)
+	}
+
+	qosService, isValidType := GetAppContext(c).GetQoSService().(*qos.NasQoSService)
+	if !isValidType || qosService == nil {
+		return fail(c, http.StatusInternalServerError, "SERVICE_ERROR", "QoS service not initialized", nil)
+	}
+
+	return ok(c, qosService.GetLiveQueues(c.Request().Context(), &nas))
+}
+
+// PushPPPProfileRate pushes the rates of a RADIUS profile to the PPP profile
+// of the same name on a NAS device
+//
+// @Summary push a profile's rate limit to a NAS PPP profile
+// @Tags QoS
+// @Param id path int true "NAS ID"
+// @Param pid path int true "RADIUS profile ID"
+// @Success 200 {object} clients.PPPProfile
+// @Router /api/v1/network/nas/{id}/ppp-profiles/{pid}/push [post]
+func PushPPPProfileRate(c echo.Context) error {
+	nasID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_ID", "Invalid NAS ID", nil)
+	}
+	profileID, err := strconv.ParseInt(c.Param("pid"), 10, 64)
+	if err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_ID", "Invalid profile ID", nil)
+	}
+
+	db := GetDB(c)
+	var nas domain.NetNas
+	if err := db.First(&nas, nasID).Error; err != nil {
+		return fail(c, http.StatusNotFound, "NOT_FOUND", "NAS device not found", nil)
+	}
+	if !nas.QoSEnabled {
+		return fail(c, http.StatusBadRequest, "QOS_DISABLED", "QoS is not enabled for this NAS device", nil)
+	}
+
+	var profile domain.RadiusProfile
+	if err := db.First(&profile, profileID).Error; err != nil {
+		return fail(c, http.StatusNotFound, "NOT_FOUND", "Profile not found", nil)
+	}
+
+	qosService, isValidType := GetAppContext(c).GetQoSService().(*qos.NasQoSService)
+	if !isValidType || qosService == nil {
+		return fail(c, http.StatusInternalServerError, "SERVICE_ERROR", "QoS service not initialized", nil)
+	}
+	if qosService.SyncPaused() {
+		zap.L().Info("PPP profile rate push skipped, QoS sync is paused", zap.Int64("nas_id", nasID))
+		return fail(c, http.StatusConflict, "QOS_SYNC_PAUSED", "QoS sync is paused", nil)
+	}
+
+	result, err := qosService.PushProfileRate(c.Request().Context(), &nas, &profile)
+	if errors.Is(err, qos.ErrPPPProfileUnsupported) {
+		return fail(c, http.StatusBadRequest, "UNSUPPORTED", err.Error(), nil)
+	}
+	if err != nil {
+		return fail(c, http.StatusBadGateway, "SYNC_ERROR", "Failed to push profile rate", err.Error())
+	}
+
+	return ok(c, result)
 }
 
 // registerQoSRoutes registers QoS routes
 func registerQoSRoutes() {
 	webserver.ApiPOST("/network/nas/:id/qos/sync", ManualTriggerQoSSync)
+	webserver.ApiPOST("/network/nas/:id/qos/queues/:qid/sync", SyncSingleQoSQueue)
+	webserver.ApiPOST("/network/nas/:id/qos/queues/:qid/plan", PlanQoSQueue)
+	webserver.ApiPOST("/network/qos/retry-failed", RetryFailedQoS)
+	webserver.ApiGET("/radius/users/:id/qos-history", ListUserQoSHistory)
 	webserver.ApiGET("/network/nas/:id/qos/status", GetQoSStatus)
 	webserver.ApiGET("/network/nas/:id/qos/queues", ListQoSQueues)
+	webserver.ApiGET("/network/nas/:id/queues/live", ListLiveQueues)
+	webserver.ApiPOST("/network/nas/:id/ppp-profiles/:pid/push", PushPPPProfileRate)
 }
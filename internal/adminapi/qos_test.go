@@ -0,0 +1,596 @@
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/app"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/qos"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/qos/clients"
+	"gorm.io/gorm"
+)
+
+// qosTestAppContext overrides the QoS service of a test application
+type qosTestAppContext struct {
+	app.AppContext
+	qosService *qos.NasQoSService
+}
+
+func (a *qosTestAppContext) GetQoSService() interface{} {
+	return a.qosService
+}
+
+// fakeQoSClient accepts every queue operation without a device
+type fakeQoSClient struct {
+	created int
+	updated int
+	listed  int
+	queues  []clients.QueueEntry
+	current *clients.QoSConfig // Returned by GetQueue
+}
+
+func (f *fakeQoSClient) CreateQueue(_ context.Context, _ *clients.QoSConfig) (string, error) {
+	f.created++
+	return "*" + strconv.Itoa(f.created), nil
+}
+
+func (f *fakeQoSClient) DeleteQueue(_ context.Context, _ string) error { return nil }
+
+func (f *fakeQoSClient) UpdateQueue(_ context.Context, _ string, _ *clients.QoSConfig) error {
+	f.updated++
+	return nil
+}
+
+func (f *fakeQoSClient) GetQueue(_ context.Context, _ string) (*clients.QoSConfig, error) {
+	return f.current, nil
+}
+
+func (f *fakeQoSClient) ListQueues(_ context.Context) ([]clients.QueueEntry, error) {
+	f.listed++
+	return f.queues, nil
+}
+
+func (f *fakeQoSClient) Close() error { return nil }
+
+// setupQoSTestApp migrates QoS tables and wires a QoS service backed by a fake client
+func setupQoSTestApp(t *testing.T, db *gorm.DB) (*qosTestAppContext, *fakeQoSClient) {
+	require.NoError(t, db.AutoMigrate(&domain.NasQoS{}, &domain.NasQoSLog{}))
+
+	client := &fakeQoSClient{}
+	svc := qos.NewNasQoSService(db, &qos.GormNasQoSRepository{DB: db}, &qos.GormNasQoSLogRepository{DB: db}, nil, nil)
+	svc.SetClientFactory(func(_ context.Context, _ *domain.NetNas) (clients.QoSClient, error) {
+		return client, nil
+	})
+
+	return &qosTestAppContext{AppContext: setupTestApp(t, db), qosService: svc}, client
+}
+
+// createTestQoSNas creates a Mikrotik NAS with QoS enabled
+func createTestQoSNas(t *testing.T, db *gorm.DB, ipaddr string) *domain.NetNas {
+	nas := createTestNas(db, "qos-nas-"+ipaddr, ipaddr)
+	require.NoError(t, db.Model(nas).Updates(domain.NetNas{VendorCode: "14988", QoSEnabled: true}).Error)
+	return nas
+}
+
+func TestSyncSingleQoSQueue(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx, client := setupQoSTestApp(t, db)
+	nas := createTestQoSNas(t, db, "192.168.9.1")
+
+	failed := &domain.NasQoS{
+		ID:         101,
+		UserID:     1,
+		NasID:      nas.ID,
+		QoSName:    "user_1",
+		UpRate:     1024,
+		DownRate:   2048,
+		Status:     "failed",
+		ErrorMsg:   "create failed: timeout",
+		RetryCount: 3,
+	}
+	require.NoError(t, db.Create(failed).Error)
+
+	tests := []struct {
+		name           string
+		nasID          string
+		queueID        string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "Failed queue becomes synced",
+			nasID:          strconv.FormatInt(nas.ID, 10),
+			queueID:        "101",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Queue on another NAS",
+			nasID:          "999",
+			queueID:        "101",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "NOT_FOUND",
+		},
+		{
+			name:           "Invalid queue ID",
+			nasID:          strconv.FormatInt(nas.ID, 10),
+			queueID:        "abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := setupTestEcho()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/network/nas/"+tt.nasID+"/qos/queues/"+tt.queueID+"/sync", nil)
+			rec := httptest.NewRecorder()
+			c := CreateTestContext(e, db, req, rec, appCtx)
+			c.SetParamNames("id", "qid")
+			c.SetParamValues(tt.nasID, tt.queueID)
+
+			require.NoError(t, SyncSingleQoSQueue(c))
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedError != "" {
+				var errResponse ErrorResponse
+				_ = json.Unmarshal(rec.Body.Bytes(), &errResponse)
+				assert.Equal(t, tt.expectedError, errResponse.Error)
+				return
+			}
+
+			var response Response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			dataBytes, _ := json.Marshal(response.Data)
+			var result domain.NasQoS
+			require.NoError(t, json.Unmarshal(dataBytes, &result))
+
+			assert.Equal(t, "synced", result.Status)
+			assert.Equal(t, 0, result.RetryCount)
+			assert.Empty(t, result.ErrorMsg)
+			assert.NotEmpty(t, result.RemoteID)
+			assert.Equal(t, 1, client.created)
+		})
+	}
+}
+
+func TestPlanQoSQueue(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx, client := setupQoSTestApp(t, db)
+	nas := createTestQoSNas(t, db, "192.168.9.4")
+	nasID := strconv.FormatInt(nas.ID, 10)
+
+	// The user's download rate was raised after the last sync
+	require.NoError(t, db.Create(&domain.NasQoS{
+		ID: 301, UserID: 1, NasID: nas.ID, QoSName: "user_1", RemoteID: "*3",
+		UpRate: 1024, DownRate: 4096, SyncedUpRate: 1024, SyncedDownRate: 2048,
+		RemoteConfig: `{"burst_limit":"2M/4M"}`, Status: "pending",
+	}).Error)
+	require.NoError(t, db.Create(&domain.NasQoS{
+		ID: 302, UserID: 2, NasID: nas.ID, QoSName: "user_2", UpRate: 512, DownRate: 512, Status: "pending",
+	}).Error)
+	client.current = &clients.QoSConfig{
+		Name: "user_1", UpRate: 1024, DownRate: 2048,
+		Extra: map[string]interface{}{clients.ExtraBurstLimit: "2000k/4000k"},
+	}
+
+	callPlan := func(t *testing.T, queueID string) (*httptest.ResponseRecorder, *qos.QueuePlan) {
+		e := setupTestEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/network/nas/"+nasID+"/qos/queues/"+queueID+"/plan", nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		c.SetParamNames("id", "qid")
+		c.SetParamValues(nasID, queueID)
+
+		require.NoError(t, PlanQoSQueue(c))
+		if rec.Code != http.StatusOK {
+			return rec, nil
+		}
+
+		var response Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		dataBytes, _ := json.Marshal(response.Data)
+		var plan qos.QueuePlan
+		require.NoError(t, json.Unmarshal(dataBytes, &plan))
+		return rec, &plan
+	}
+
+	t.Run("Rate change is highlighted", func(t *testing.T) {
+		_, plan := callPlan(t, "301")
+		require.NotNil(t, plan)
+		assert.Equal(t, qos.PlanActionUpdate, plan.Action)
+		require.NotNil(t, plan.Current)
+		assert.Equal(t, 2048, plan.Current.DownRate)
+		assert.Equal(t, 4096, plan.Desired.DownRate)
+		require.Len(t, plan.Changes, 1, "equivalent burst notations must not show as a change")
+		assert.Equal(t, "down_rate", plan.Changes[0].Field)
+		assert.EqualValues(t, 2048, plan.Changes[0].Current)
+		assert.EqualValues(t, 4096, plan.Changes[0].Desired)
+
+		// Planning writes nothing
+		assert.Zero(t, client.updated)
+		var stored domain.NasQoS
+		require.NoError(t, db.First(&stored, 301).Error)
+		assert.Equal(t, "pending", stored.Status)
+		assert.Equal(t, 2048, stored.SyncedDownRate)
+	})
+
+	t.Run("Unsynced queue would be created", func(t *testing.T) {
+		_, plan := callPlan(t, "302")
+		require.NotNil(t, plan)
+		assert.Equal(t, qos.PlanActionCreate, plan.Action)
+		assert.Nil(t, plan.Current)
+		assert.Len(t, plan.Changes, 3)
+		assert.Zero(t, client.created)
+	})
+
+	t.Run("Unknown queue", func(t *testing.T) {
+		rec, _ := callPlan(t, "999")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestListLiveQueues(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx, client := setupQoSTestApp(t, db)
+	nas := createTestQoSNas(t, db, "192.168.9.2")
+	nasID := strconv.FormatInt(nas.ID, 10)
+
+	client.queues = []clients.QueueEntry{
+		{ID: "*1", Name: "user_1", Target: "10.0.0.1/32", UpRate: 1024, DownRate: 2048},
+		{ID: "*2", Name: "user_2", Target: "10.0.0.2/32", UpRate: 512, DownRate: 512, Disabled: true},
+	}
+
+	callLive := func(t *testing.T) *qos.LiveQueues {
+		e := setupTestEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/network/nas/"+nasID+"/queues/live", nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		c.SetParamNames("id")
+		c.SetParamValues(nasID)
+
+		require.NoError(t, ListLiveQueues(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		dataBytes, _ := json.Marshal(response.Data)
+		var result qos.LiveQueues
+		require.NoError(t, json.Unmarshal(dataBytes, &result))
+		return &result
+	}
+
+	t.Run("Live queues are cached", func(t *testing.T) {
+		result := callLive(t)
+		assert.Equal(t, "live", result.Source)
+		assert.False(t, result.Stale)
+		require.Len(t, result.Queues, 2)
+		assert.Equal(t, "10.0.0.1/32", result.Queues[0].Target)
+		assert.True(t, result.Queues[1].Disabled)
+
+		callLive(t)
+		assert.Equal(t, 1, client.listed)
+	})
+
+	t.Run("Unreachable device falls back to snapshot", func(t *testing.T) {
+		syncedAt := time.Now().Add(-time.Hour)
+		require.NoError(t, db.Create(&domain.NasQoS{
+			ID: 201, UserID: 1, NasID: nas.ID, QoSName: "user_1", RemoteID: "*9",
+			UpRate: 1024, DownRate: 2048, SyncedUpRate: 1024, SyncedDownRate: 2048,
+			Status: "synced", SyncedAt: &syncedAt,
+		}).Error)
+
+		svc := appCtx.qosService
+		offline := qos.NewNasQoSService(db, &qos.GormNasQoSRepository{DB: db}, &qos.GormNasQoSLogRepository{DB: db}, nil, nil)
+		offline.SetClientFactory(func(_ context.Context, _ *domain.NetNas) (clients.QoSClient, error) {
+			return nil, errors.New("connection refused")
+		})
+		appCtx.qosService = offline
+		defer func() { appCtx.qosService = svc }()
+
+		result := callLive(t)
+		assert.Equal(t, "snapshot", result.Source)
+		assert.True(t, result.Stale)
+		assert.Contains(t, result.Error, "connection refused")
+		require.Len(t, result.Queues, 1)
+		assert.Equal(t, "*9", result.Queues[0].ID)
+		assert.Equal(t, 2048, result.Queues[0].DownRate)
+		assert.WithinDuration(t, syncedAt, result.FetchedAt, time.Second)
+	})
+}
+
+// fakePPPClient is a fake client that also manages PPP profiles
+type fakePPPClient struct {
+	fakeQoSClient
+	profiles map[string]*clients.PPPProfile
+}
+
+func (f *fakePPPClient) GetPPPProfile(_ context.Context, name string) (*clients.PPPProfile, error) {
+	profile, ok := f.profiles[name]
+	if !ok {
+		return nil, errors.New("ppp profile not found: " + name)
+	}
+	return profile, nil
+}
+
+func (f *fakePPPClient) SetPPPProfileRateLimit(_ context.Context, name string, upRate, downRate int) error {
+	f.profiles[name].UpRate = upRate
+	f.profiles[name].DownRate = downRate
+	return nil
+}
+
+func TestPushPPPProfileRate(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx, _ := setupQoSTestApp(t, db)
+	nas := createTestQoSNas(t, db, "192.168.9.3")
+	nasID := strconv.FormatInt(nas.ID, 10)
+
+	plan := &domain.RadiusProfile{ID: 31, Name: "plan-10m", UpRate: 5000, DownRate: 10000, Status: "enabled"}
+	missing := &domain.RadiusProfile{ID: 32, Name: "plan-50m", UpRate: 25000, DownRate: 50000, Status: "enabled"}
+	require.NoError(t, db.Create(plan).Error)
+	require.NoError(t, db.Create(missing).Error)
+
+	pppClient := &fakePPPClient{profiles: map[string]*clients.PPPProfile{
+		"plan-10m": {ID: "*2", Name: "plan-10m", UpRate: 1024, DownRate: 1024},
+	}}
+	appCtx.qosService.SetClientFactory(func(_ context.Context, _ *domain.NetNas) (clients.QoSClient, error) {
+		return pppClient, nil
+	})
+
+	call := func(t *testing.T, profileID string) *httptest.ResponseRecorder {
+		e := setupTestEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/network/nas/"+nasID+"/ppp-profiles/"+profileID+"/push", nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		c.SetParamNames("id", "pid")
+		c.SetParamValues(nasID, profileID)
+		require.NoError(t, PushPPPProfileRate(c))
+		return rec
+	}
+	errorCode := func(rec *httptest.ResponseRecorder) string {
+		var errResponse ErrorResponse
+		_ = json.Unmarshal(rec.Body.Bytes(), &errResponse)
+		return errResponse.Error
+	}
+
+	t.Run("Profile rate is pushed", func(t *testing.T) {
+		rec := call(t, "31")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		dataBytes, _ := json.Marshal(response.Data)
+		var result clients.PPPProfile
+		require.NoError(t, json.Unmarshal(dataBytes, &result))
+		assert.Equal(t, "plan-10m", result.Name)
+		assert.Equal(t, 5000, result.UpRate)
+		assert.Equal(t, 10000, result.DownRate)
+	})
+
+	t.Run("Profile missing on device", func(t *testing.T) {
+		rec := call(t, "32")
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+		assert.Equal(t, "SYNC_ERROR", errorCode(rec))
+	})
+
+	t.Run("Unknown profile", func(t *testing.T) {
+		rec := call(t, "999")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Sync paused", func(t *testing.T) {
+		appCtx.qosService.SetSyncPaused(func() bool { return true })
+		defer appCtx.qosService.SetSyncPaused(nil)
+		pppClient.profiles["plan-10m"].UpRate = 1024
+
+		rec := call(t, "31")
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "QOS_SYNC_PAUSED", errorCode(rec))
+		assert.Equal(t, 1024, pppClient.profiles["plan-10m"].UpRate)
+	})
+
+	t.Run("Vendor without PPP profiles", func(t *testing.T) {
+		appCtx.qosService.SetClientFactory(func(_ context.Context, _ *domain.NetNas) (clients.QoSClient, error) {
+			return &fakeQoSClient{}, nil
+		})
+		rec := call(t, "31")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "UNSUPPORTED", errorCode(rec))
+	})
+}
+
+func TestListQoSQueuesPaging(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx, _ := setupQoSTestApp(t, db)
+	nas := createTestQoSNas(t, db, "192.168.9.4")
+	nasID := strconv.FormatInt(nas.ID, 10)
+
+	for i := int64(1); i <= 5; i++ {
+		require.NoError(t, db.Create(&domain.NasQoS{
+			ID: 300 + i, UserID: i, NasID: nas.ID, QoSName: "user_" + strconv.FormatInt(i, 10),
+			UpRate: 1024, DownRate: 1024, Status: "pending",
+		}).Error)
+	}
+
+	call := func(query string) PageResult {
+		e := setupTestEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/network/nas/"+nasID+"/qos/queues?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		c.SetParamNames("id")
+		c.SetParamValues(nasID)
+		require.NoError(t, ListQoSQueues(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var result PageResult
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+		return result
+	}
+
+	first := call("page=1&perPage=2")
+	assert.Equal(t, int64(5), first.Total)
+	assert.Equal(t, 3, first.TotalPages)
+	assert.True(t, first.HasNext)
+	assert.Len(t, first.Data, 2)
+
+	last := call("page=3&perPage=2")
+	assert.False(t, last.HasNext)
+	assert.Len(t, last.Data, 1)
+}
+
+func TestRetryFailedQoS(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx, _ := setupQoSTestApp(t, db)
+	nasA := createTestQoSNas(t, db, "192.168.9.5")
+	nasB := createTestQoSNas(t, db, "192.168.9.6")
+
+	records := []*domain.NasQoS{
+		{ID: 401, UserID: 1, NasID: nasA.ID, NasAddr: nasA.Ipaddr, Status: "failed", ErrorMsg: "timeout", DeviceError: "fatal: closed", RetryCount: 3},
+		{ID: 402, UserID: 2, NasID: nasA.ID, NasAddr: nasA.Ipaddr, Status: "failed", ErrorMsg: "timeout", RetryCount: 2},
+		{ID: 403, UserID: 3, NasID: nasB.ID, NasAddr: nasB.Ipaddr, Status: "failed", ErrorMsg: "timeout", RetryCount: 3},
+		{ID: 404, UserID: 4, NasID: nasB.ID, NasAddr: nasB.Ipaddr, Status: "synced"},
+	}
+	for _, record := range records {
+		require.NoError(t, db.Create(record).Error)
+	}
+
+	call := func(query string) (*httptest.ResponseRecorder, qos.RequeueResult) {
+		e := setupTestEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/network/qos/retry-failed?"+query, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, RetryFailedQoS(CreateTestContext(e, db, req, rec, appCtx)))
+
+		var response Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		dataBytes, _ := json.Marshal(response.Data) //nolint:errcheck
+		var result qos.RequeueResult
+		require.NoError(t, json.Unmarshal(dataBytes, &result))
+		return rec, result
+	}
+
+	rec, _ := call("limit=0")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, result := call("")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int64(3), result.Requeued)
+	assert.Equal(t, int64(0), result.Remaining)
+	counts := map[int64]int64{}
+	for _, nas := range result.Nas {
+		counts[nas.NasID] = nas.Requeued
+	}
+	assert.Equal(t, map[int64]int64{nasA.ID: 2, nasB.ID: 1}, counts)
+
+	for _, id := range []int64{401, 402, 403} {
+		var stored domain.NasQoS
+		require.NoError(t, db.First(&stored, id).Error)
+		assert.Equal(t, "pending", stored.Status)
+		assert.Zero(t, stored.RetryCount)
+		assert.Empty(t, stored.ErrorMsg)
+		assert.Empty(t, stored.DeviceError)
+	}
+	var synced domain.NasQoS
+	require.NoError(t, db.First(&synced, 404).Error)
+	assert.Equal(t, "synced", synced.Status)
+}
+
+func TestSyncSingleQoSQueue_Paused(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx, client := setupQoSTestApp(t, db)
+	nas := createTestQoSNas(t, db, "192.168.9.7")
+	nasID := strconv.FormatInt(nas.ID, 10)
+	require.NoError(t, db.Create(&domain.NasQoS{
+		ID: 501, UserID: 1, NasID: nas.ID, QoSName: "user_1", UpRate: 1024, DownRate: 1024, Status: "pending",
+	}).Error)
+	appCtx.qosService.SetSyncPaused(func() bool { return true })
+
+	e := setupTestEcho()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/network/nas/"+nasID+"/qos/queues/501/sync", nil)
+	rec := httptest.NewRecorder()
+	c := CreateTestContext(e, db, req, rec, appCtx)
+	c.SetParamNames("id", "qid")
+	c.SetParamValues(nasID, "501")
+	require.NoError(t, SyncSingleQoSQueue(c))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	var errResponse ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errResponse))
+	assert.Equal(t, "QOS_SYNC_PAUSED", errResponse.Error)
+	assert.Zero(t, client.created)
+}
+
+func TestListUserQoSHistory(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx, _ := setupQoSTestApp(t, db)
+	nasA := createTestQoSNas(t, db, "192.168.9.8")
+	nasB := createTestQoSNas(t, db, "192.168.9.9")
+	user := createTestUser(db, "history-user", 0)
+	other := createTestUser(db, "other-user", 0)
+
+	require.NoError(t, db.Create(&domain.NasQoS{
+		ID: 601, UserID: user.ID, NasID: nasA.ID, QoSName: "user_a", UpRate: 2048, DownRate: 4096, Status: "synced",
+	}).Error)
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	logs := []*domain.NasQoSLog{
+		{ID: 1, QoSID: 601, UserID: user.ID, NasID: nasA.ID, Action: "create", Status: "failure", ErrorMsg: "timeout", ExecutedAt: base},
+		{ID: 2, QoSID: 601, UserID: user.ID, NasID: nasA.ID, Action: "create", Status: "success", ExecutedAt: base.Add(time.Minute)},
+		// The queue on NAS B has been deleted since
+		{ID: 3, QoSID: 602, UserID: user.ID, NasID: nasB.ID, Action: "delete", Status: "success", ExecutedAt: base.Add(2 * time.Minute)},
+		{ID: 4, QoSID: 603, UserID: other.ID, NasID: nasA.ID, Action: "create", Status: "success", ExecutedAt: base.Add(3 * time.Minute)},
+	}
+	for _, log := range logs {
+		require.NoError(t, db.Create(log).Error)
+	}
+
+	call := func(id, query string) (*httptest.ResponseRecorder, PageResult, []qosHistoryEntry) {
+		e := setupTestEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/radius/users/"+id+"/qos-history?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, ListUserQoSHistory(c))
+
+		var result PageResult
+		_ = json.Unmarshal(rec.Body.Bytes(), &result) //nolint:errcheck
+		dataBytes, _ := json.Marshal(result.Data)     //nolint:errcheck
+		var entries []qosHistoryEntry
+		_ = json.Unmarshal(dataBytes, &entries) //nolint:errcheck
+		return rec, result, entries
+	}
+
+	userID := strconv.FormatInt(user.ID, 10)
+	rec, result, entries := call(userID, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int64(3), result.Total)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []int64{3, 2, 1}, []int64{entries[0].ID, entries[1].ID, entries[2].ID}, "newest first")
+	assert.Equal(t, nasB.Ipaddr, entries[0].NasAddr)
+	assert.Empty(t, entries[0].QueueStatus, "deleted queue")
+	assert.Equal(t, int64(601), entries[1].QoSID)
+	assert.Equal(t, "user_a", entries[1].QoSName)
+	assert.Equal(t, 2048, entries[1].UpRate)
+	assert.Equal(t, "synced", entries[1].QueueStatus)
+	assert.Equal(t, nasA.Name, entries[1].NasName)
+
+	_, result, entries = call(userID, "status=failure")
+	assert.Equal(t, int64(1), result.Total)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "timeout", entries[0].ErrorMsg)
+
+	_, result, entries = call(userID, "page=2&perPage=2")
+	assert.Equal(t, 2, result.TotalPages)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(1), entries[0].ID)
+
+	rec, _, _ = call("999999", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
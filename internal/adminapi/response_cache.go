@@ -0,0 +1,154 @@
+package adminapi
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Cached read endpoints and the system setting holding their TTL in seconds
+const (
+	cacheDashboardStats = "dashboard.stats"
+	cacheOnlineStats    = "online.stats"
+)
+
+var responseCacheTTLKeys = map[string]string{
+	cacheDashboardStats: "DashboardStatsCacheSeconds",
+	cacheOnlineStats:    "OnlineStatsCacheSeconds",
+}
+
+// responseCache keeps successful responses of expensive read endpoints in
+// memory, keyed by endpoint, path parameters and query string
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]*responseCacheEntry
+}
+
+type responseCacheEntry struct {
+	endpoint    string
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+var apiResponseCache = &responseCache{entries: make(map[string]*responseCacheEntry)}
+
+func (rc *responseCache) get(key string) (*responseCacheEntry, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, found := rc.entries[key]
+	if !found {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(rc.entries, key)
+		return nil, false
+	}
+	return entry, true
+}
+
+func (rc *responseCache) set(key string, entry *responseCacheEntry) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries[key] = entry
+}
+
+// invalidate drops every cached response of the endpoints
+func (rc *responseCache) invalidate(endpoints ...string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for key, entry := range rc.entries {
+		for _, endpoint := range endpoints {
+			if entry.endpoint == endpoint {
+				delete(rc.entries, key)
+				break
+			}
+		}
+	}
+}
+
+// responseCacheTTL reads the configured TTL of the endpoint, 0 disables caching
+func responseCacheTTL(c echo.Context, endpoint string) time.Duration {
+	cfg := GetConfig(c)
+	if cfg == nil {
+		return 0
+	}
+	seconds := cfg.GetInt("system", responseCacheTTLKeys[endpoint])
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func responseCacheKey(c echo.Context, endpoint string) string {
+	// Encode sorts the query by key, so parameter order does not matter
+	return endpoint + "|" + strings.Join(c.ParamValues(), "/") + "?" + c.QueryParams().Encode()
+}
+
+// cachedResponse serves repeated GET requests of the endpoint from the
+// response cache while its TTL setting is above zero. Only 200 responses
+// are stored; the X-Cache header reports HIT or MISS.
+func cachedResponse(endpoint string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ttl := responseCacheTTL(c, endpoint)
+			if ttl == 0 {
+				return next(c)
+			}
+
+			key := responseCacheKey(c, endpoint)
+			if entry, found := apiResponseCache.get(key); found {
+				c.Response().Header().Set("X-Cache", "HIT")
+				return c.Blob(http.StatusOK, entry.contentType, entry.body)
+			}
+
+			c.Response().Header().Set("X-Cache", "MISS")
+			res := c.Response()
+			writer := &bodyCaptureWriter{ResponseWriter: res.Writer}
+			res.Writer = writer
+			defer func() { res.Writer = writer.ResponseWriter }()
+
+			if err := next(c); err != nil {
+				return err
+			}
+			if res.Status == http.StatusOK {
+				apiResponseCache.set(key, &responseCacheEntry{
+					endpoint:    endpoint,
+					contentType: res.Header().Get(echo.HeaderContentType),
+					body:        writer.body.Bytes(),
+					expiresAt:   time.Now().Add(ttl),
+				})
+			}
+			return nil
+		}
+	}
+}
+
+// invalidatesCache drops the cached responses of the endpoints after a
+// successful write
+func invalidatesCache(endpoints ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			if err == nil && c.Response().Status < http.StatusBadRequest {
+				apiResponseCache.invalidate(endpoints...)
+			}
+			return err
+		}
+	}
+}
+
+// bodyCaptureWriter copies the response body while it is written
+type bodyCaptureWriter struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
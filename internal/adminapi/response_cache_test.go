@@ -0,0 +1,64 @@
+package adminapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+)
+
+func TestCachedResponse(t *testing.T) {
+	db, e, appCtx := CreateTestAppContext(t)
+	apiResponseCache.invalidate(cacheDashboardStats)
+	t.Cleanup(func() { apiResponseCache.invalidate(cacheDashboardStats) })
+	require.NoError(t, appCtx.ConfigMgr().Set("system", "DashboardStatsCacheSeconds", "60"))
+
+	getStats := cachedResponse(cacheDashboardStats)(GetDashboardStats)
+	call := func(t *testing.T) (string, int64) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard/stats", nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, getStats(CreateTestContext(e, db, req, rec, appCtx)))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		dataBytes, _ := json.Marshal(response.Data) //nolint:errcheck
+		var stats DashboardStats
+		require.NoError(t, json.Unmarshal(dataBytes, &stats))
+		return rec.Header().Get("X-Cache"), stats.TotalUsers
+	}
+	write := func(t *testing.T, handler echo.HandlerFunc) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/users", nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, invalidatesCache(cacheDashboardStats)(handler)(CreateTestContext(e, db, req, rec, appCtx)))
+	}
+
+	state, total := call(t)
+	assert.Equal(t, "MISS", state)
+	assert.Equal(t, int64(0), total)
+
+	require.NoError(t, db.Create(&domain.RadiusUser{ID: 1, Username: "cached", Status: "enabled"}).Error)
+	state, total = call(t)
+	assert.Equal(t, "HIT", state)
+	assert.Equal(t, int64(0), total, "served from cache within the TTL")
+
+	write(t, func(c echo.Context) error {
+		return fail(c, http.StatusBadRequest, "INVALID_REQUEST", "rejected", nil)
+	})
+	state, _ = call(t)
+	assert.Equal(t, "HIT", state, "failed writes keep the cache")
+
+	write(t, func(c echo.Context) error { return ok(c, nil) })
+	state, total = call(t)
+	assert.Equal(t, "MISS", state)
+	assert.Equal(t, int64(1), total)
+
+	require.NoError(t, appCtx.ConfigMgr().Set("system", "DashboardStatsCacheSeconds", "0"))
+	state, _ = call(t)
+	assert.Empty(t, state, "a zero TTL disables the cache")
+}
@@ -42,6 +42,31 @@ func paged(c echo.Context, data interface{}, total int64, page, pageSize int) er
 	})
 }
 
+// PageResult is a self-describing page of a list, carrying the page count and
+// whether a next page exists alongside the items
+type PageResult struct {
+	Data       interface{} `json:"data"`
+	Total      int64       `json:"total"`
+	Page       int         `json:"page"`
+	PerPage    int         `json:"per_page"`
+	TotalPages int         `json:"total_pages"`
+	HasNext    bool        `json:"has_next"`
+}
+
+// newPageResult computes the page count and next-page flag of a list page
+func newPageResult(data interface{}, total int64, page, perPage int) PageResult {
+	result := PageResult{Data: data, Total: total, Page: page, PerPage: perPage}
+	if perPage > 0 && total > 0 {
+		result.TotalPages = int((total + int64(perPage) - 1) / int64(perPage))
+	}
+	result.HasNext = page < result.TotalPages
+	return result
+}
+
+func pageResult(c echo.Context, data interface{}, total int64, page, perPage int) error {
+	return c.JSON(http.StatusOK, newPageResult(data, total, page, perPage))
+}
+
 func fail(c echo.Context, status int, code, message string, details interface{}) error {
 	if status == 0 {
 		status = http.StatusBadRequest
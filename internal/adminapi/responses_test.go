@@ -0,0 +1,37 @@
+package adminapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPageResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		total      int64
+		page       int
+		perPage    int
+		totalPages int
+		hasNext    bool
+	}{
+		{name: "Empty list", total: 0, page: 1, perPage: 20, totalPages: 0, hasNext: false},
+		{name: "Single partial page", total: 5, page: 1, perPage: 20, totalPages: 1, hasNext: false},
+		{name: "Exactly one full page", total: 20, page: 1, perPage: 20, totalPages: 1, hasNext: false},
+		{name: "One item over a full page", total: 21, page: 1, perPage: 20, totalPages: 2, hasNext: true},
+		{name: "Last page", total: 21, page: 2, perPage: 20, totalPages: 2, hasNext: false},
+		{name: "Page beyond the end", total: 21, page: 5, perPage: 20, totalPages: 2, hasNext: false},
+		{name: "Zero page size", total: 10, page: 1, perPage: 0, totalPages: 0, hasNext: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newPageResult([]int{}, tt.total, tt.page, tt.perPage)
+			assert.Equal(t, tt.totalPages, result.TotalPages)
+			assert.Equal(t, tt.hasNext, result.HasNext)
+			assert.Equal(t, tt.total, result.Total)
+			assert.Equal(t, tt.page, result.Page)
+			assert.Equal(t, tt.perPage, result.PerPage)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package adminapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/internal/webserver"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSearchLimit = 5
+	maxSearchLimit     = 50
+)
+
+// SearchHit is a single entity matching a global search
+type SearchHit struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle"`
+	Link     string `json:"link"`
+}
+
+// SearchGroup holds the top matches of one entity type
+type SearchGroup struct {
+	Type  string      `json:"type"`
+	Total int64       `json:"total"`
+	Items []SearchHit `json:"items"`
+}
+
+// searchSource describes how one entity type is searched
+type searchSource struct {
+	Type    string
+	Model   interface{}
+	Columns []string
+	Order   string
+	Hits    func(query *gorm.DB) ([]SearchHit, error)
+}
+
+// searchSources lists the searchable entity types in display order
+var searchSources = []searchSource{
+	{
+		Type:    "users",
+		Model:   &domain.RadiusUser{},
+		Columns: []string{"username", "realname", "mobile", "ip_addr", "mac_addr"},
+		Order:   "username ASC",
+		Hits: func(query *gorm.DB) ([]SearchHit, error) {
+			var users []domain.RadiusUser
+			if err := query.Find(&users).Error; err != nil {
+				return nil, err
+			}
+			hits := make([]SearchHit, 0, len(users))
+			for _, u := range users {
+				id := strconv.FormatInt(u.ID, 10)
+				hits = append(hits, SearchHit{ID: id, Title: u.Username, Subtitle: u.Realname, Link: "/radius/users/" + id + "/show"})
+			}
+			return hits, nil
+		},
+	},
+	{
+		Type:    "nas",
+		Model:   &domain.NetNas{},
+		Columns: []string{"name", "identifier", "ipaddr", "hostname"},
+		Order:   "name ASC",
+		Hits: func(query *gorm.DB) ([]SearchHit, error) {
+			var devices []domain.NetNas
+			if err := query.Find(&devices).Error; err != nil {
+				return nil, err
+			}
+			hits := make([]SearchHit, 0, len(devices))
+			for _, d := range devices {
+				id := strconv.FormatInt(d.ID, 10)
+				hits = append(hits, SearchHit{ID: id, Title: d.Name, Subtitle: d.Ipaddr, Link: "/network/nas/" + id + "/show"})
+			}
+			return hits, nil
+		},
+	},
+	{
+		Type:    "profiles",
+		Model:   &domain.RadiusProfile{},
+		Columns: []string{"name", "addr_pool", "domain"},
+		Order:   "name ASC",
+		Hits: func(query *gorm.DB) ([]SearchHit, error) {
+			var profiles []domain.RadiusProfile
+			if err := query.Find(&profiles).Error; err != nil {
+				return nil, err
+			}
+			hits := make([]SearchHit, 0, len(profiles))
+			for _, p := range profiles {
+				id := strconv.FormatInt(p.ID, 10)
+				hits = append(hits, SearchHit{ID: id, Title: p.Name, Subtitle: p.AddrPool, Link: "/radius/profiles/" + id + "/show"})
+			}
+			return hits, nil
+		},
+	},
+	{
+		Type:    "nodes",
+		Model:   &domain.NetNode{},
+		Columns: []string{"name", "remark", "tags"},
+		Order:   "name ASC",
+		Hits: func(query *gorm.DB) ([]SearchHit, error) {
+			var nodes []domain.NetNode
+			if err := query.Find(&nodes).Error; err != nil {
+				return nil, err
+			}
+			hits := make([]SearchHit, 0, len(nodes))
+			for _, n := range nodes {
+				id := strconv.FormatInt(n.ID, 10)
+				hits = append(hits, SearchHit{ID: id, Title: n.Name, Subtitle: n.Remark, Link: "/network/nodes/" + id + "/show"})
+			}
+			return hits, nil
+		},
+	},
+	{
+		Type:    "online",
+		Model:   &domain.RadiusOnline{},
+		Columns: []string{"username", "framed_ipaddr", "mac_addr", "acct_session_id"},
+		Order:   "acct_start_time DESC",
+		Hits: func(query *gorm.DB) ([]SearchHit, error) {
+			var sessions []domain.RadiusOnline
+			if err := query.Find(&sessions).Error; err != nil {
+				return nil, err
+			}
+			hits := make([]SearchHit, 0, len(sessions))
+			for _, s := range sessions {
+				id := strconv.FormatInt(s.ID, 10)
+				hits = append(hits, SearchHit{ID: id, Title: s.Username, Subtitle: s.FramedIpaddr, Link: "/radius/online/" + id + "/show"})
+			}
+			return hits, nil
+		},
+	},
+}
+
+// GlobalSearch searches users, NAS devices, profiles, nodes and online
+// sessions, returning the top matches of every entity type
+// @Summary global search
+// @Tags Search
+// @Param q query string true "Search term"
+// @Param types query string false "Comma-separated entity types to search"
+// @Param limit query int false "Matches per entity type (1-50, default 5)"
+// @Param page query int false "Page of matches within each entity type"
+// @Success 200 {array} SearchGroup
+// @Router /api/v1/search [get]
+func GlobalSearch(c echo.Context) error {
+	term := strings.TrimSpace(c.QueryParam("q"))
+	if term == "" {
+		return fail(c, http.StatusBadRequest, "INVALID_QUERY", "Search term is required", nil)
+	}
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit < 1 || limit > maxSearchLimit {
+		limit = defaultSearchLimit
+	}
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	wanted := make(map[string]bool)
+	for _, t := range strings.Split(c.QueryParam("types"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			wanted[strings.ToLower(t)] = true
+		}
+	}
+
+	db := GetRequestDB(c)
+	groups := make([]SearchGroup, 0, len(searchSources))
+	for _, source := range searchSources {
+		if len(wanted) > 0 && !wanted[source.Type] {
+			continue
+		}
+
+		query := whereContainsFold(db.Model(source.Model), term, source.Columns...)
+		group := SearchGroup{Type: source.Type}
+		if err := query.Session(&gorm.Session{}).Count(&group.Total).Error; err != nil {
+			return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to search "+source.Type, err.Error())
+		}
+		if group.Total == 0 {
+			continue
+		}
+
+		group.Items, err = source.Hits(query.Order(source.Order).Offset((page - 1) * limit).Limit(limit))
+		if err != nil {
+			return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to search "+source.Type, err.Error())
+		}
+		groups = append(groups, group)
+	}
+
+	return ok(c, groups)
+}
+
+// registerSearchRoutes registers global search routes
+func registerSearchRoutes() {
+	webserver.ApiGET("/search", GlobalSearch)
+}
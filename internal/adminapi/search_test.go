@@ -0,0 +1,79 @@
+package adminapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+)
+
+func TestGlobalSearch(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx := setupTestApp(t, db)
+
+	createTestNas(db, "Edge-Router-01", "10.10.0.1")
+	createTestNas(db, "core-switch", "10.10.0.2")
+	require.NoError(t, db.Create(&domain.NetNode{ID: 1, Name: "edge-pop-north"}).Error)
+	require.NoError(t, db.Create(&domain.RadiusUser{ID: 1, Username: "alice", Realname: "Alice Smith", Status: "enabled"}).Error)
+
+	search := func(t *testing.T, query string) (int, []SearchGroup) {
+		e := setupTestEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/search?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+
+		require.NoError(t, GlobalSearch(c))
+		if rec.Code != http.StatusOK {
+			return rec.Code, nil
+		}
+
+		var response Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		dataBytes, _ := json.Marshal(response.Data)
+		var groups []SearchGroup
+		require.NoError(t, json.Unmarshal(dataBytes, &groups))
+		return rec.Code, groups
+	}
+
+	t.Run("Term matches NAS and node groups", func(t *testing.T) {
+		_, groups := search(t, "q=EDGE")
+		require.Len(t, groups, 2)
+
+		assert.Equal(t, "nas", groups[0].Type)
+		assert.Equal(t, int64(1), groups[0].Total)
+		require.Len(t, groups[0].Items, 1)
+		assert.Equal(t, "Edge-Router-01", groups[0].Items[0].Title)
+		assert.Equal(t, "/network/nas/"+groups[0].Items[0].ID+"/show", groups[0].Items[0].Link)
+
+		assert.Equal(t, "nodes", groups[1].Type)
+		assert.Equal(t, "edge-pop-north", groups[1].Items[0].Title)
+	})
+
+	t.Run("Types restrict the searched entities", func(t *testing.T) {
+		_, groups := search(t, "q=edge&types=nodes")
+		require.Len(t, groups, 1)
+		assert.Equal(t, "nodes", groups[0].Type)
+	})
+
+	t.Run("Limit bounds each group", func(t *testing.T) {
+		_, groups := search(t, "q=10.10.0&limit=1")
+		require.Len(t, groups, 1)
+		assert.Equal(t, int64(2), groups[0].Total)
+		assert.Len(t, groups[0].Items, 1)
+	})
+
+	t.Run("Wildcards match literally", func(t *testing.T) {
+		_, groups := search(t, "q="+url.QueryEscape("%"))
+		assert.Empty(t, groups)
+	})
+
+	t.Run("Missing term", func(t *testing.T) {
+		code, _ := search(t, "q=")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors"
 	"github.com/talkincode/toughradius/v9/internal/webserver"
 	"go.uber.org/zap"
 	"layeh.com/radius"
@@ -72,7 +73,7 @@ func parseSessionTime(s string) (time.Time, error) {
 // @Success 200 {object} ListResponse
 // @Router /api/v1/sessions [get]
 func ListOnlineSessions(c echo.Context) error {
-	db := GetDB(c)
+	db := GetRequestDB(c)
 
 	page, _ := strconv.Atoi(c.QueryParam("page"))
 	perPage, _ := strconv.Atoi(c.QueryParam("perPage"))
@@ -249,9 +250,139 @@ func DeleteOnlineSession(c echo.Context) error {
 	})
 }
 
+// SessionRateRequest is the payload for a live rate change
+type SessionRateRequest struct {
+	UpRate   int `json:"up_rate" validate:"required,gte=1"`   // Upload rate in Kbps
+	DownRate int `json:"down_rate" validate:"required,gte=1"` // Download rate in Kbps
+}
+
+// coaExchange sends a CoA packet and waits for the NAS reply, replaceable in tests
+var coaExchange = func(ctx context.Context, pkt *radius.Packet, addr string) (*radius.Packet, error) {
+	client := &radius.Client{
+		Retry: time.Second * 2,
+	}
+	return client.Exchange(ctx, pkt, addr)
+}
+
+// buildRateCoAPacket builds a CoA-Request carrying the vendor bandwidth
+// attributes for the NAS serving the session
+func buildRateCoAPacket(nas *domain.NetNas, session *domain.RadiusOnline, upRate, downRate int) (*radius.Packet, bool) {
+	pkt := radius.New(radius.CodeCoARequest, []byte(nas.Secret))
+	_ = rfc2866.AcctSessionID_SetString(pkt, session.AcctSessionId) //nolint:errcheck
+	_ = rfc2865.UserName_SetString(pkt, session.Username)           //nolint:errcheck
+	if !vendors.SetRateLimit(pkt, nas.VendorCode, upRate, downRate) {
+		return nil, false
+	}
+	return pkt, true
+}
+
+// coaAddress returns the CoA endpoint of a NAS (default port 3799)
+func coaAddress(nas *domain.NetNas) string {
+	port := 3799
+	if nas.CoaPort > 0 {
+		port = nas.CoaPort
+	}
+	return net.JoinHostPort(nas.Ipaddr, strconv.Itoa(port))
+}
+
+// ChangeOnlineSessionRate changes the bandwidth of an online session via CoA
+// @Summary change the rate of an online session
+// @Tags OnlineSession
+// @Param session_id path string true "Accounting session ID"
+// @Param body body SessionRateRequest true "New rates in Kbps"
+// @Success 200 {object} Response
+// @Router /api/v1/radius/online/{session_id}/rate [post]
+func ChangeOnlineSessionRate(c echo.Context) error {
+	sessionID := strings.TrimSpace(c.Param("session_id"))
+	if sessionID == "" {
+		return fail(c, http.StatusBadRequest, "INVALID_SESSION_ID", "Session ID is required", nil)
+	}
+
+	var req SessionRateRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_REQUEST", "Unable to parse request parameters", err.Error())
+	}
+	if err := c.Validate(&req); err != nil {
+		return handleValidationError(c, err)
+	}
+
+	var session domain.RadiusOnline
+	if err := GetDB(c).Where("acct_session_id = ?", sessionID).First(&session).Error; err != nil {
+		return fail(c, http.StatusNotFound, "NOT_FOUND", "Session not found", nil)
+	}
+
+	var nas domain.NetNas
+	if err := GetDB(c).Where("ipaddr = ?", session.NasAddr).First(&nas).Error; err != nil {
+		return fail(c, http.StatusNotFound, "NAS_NOT_FOUND", "NAS not found for session", nil)
+	}
+
+	pkt, supported := buildRateCoAPacket(&nas, &session, req.UpRate, req.DownRate)
+	if !supported {
+		return fail(c, http.StatusBadRequest, "UNSUPPORTED_VENDOR", "NAS vendor does not support live rate change", nas.VendorCode)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	addr := coaAddress(&nas)
+	response, err := coaExchange(ctx, pkt, addr)
+	if err != nil {
+		zap.L().Error("Failed to send CoA rate change",
+			zap.Error(err),
+			zap.String("nas_addr", addr),
+			zap.String("username", session.Username),
+			zap.String("acct_session_id", session.AcctSessionId),
+			zap.String("namespace", "adminapi"))
+		return fail(c, http.StatusBadGateway, "COA_FAILED", "Failed to send CoA request", err.Error())
+	}
+	if response.Code != radius.CodeCoAACK {
+		return fail(c, http.StatusBadGateway, "COA_REJECTED", "NAS rejected the rate change", nil)
+	}
+
+	zap.L().Info("CoA rate change applied",
+		zap.String("nas_addr", addr),
+		zap.String("username", session.Username),
+		zap.Int("up_rate", req.UpRate),
+		zap.Int("down_rate", req.DownRate),
+		zap.String("namespace", "adminapi"))
+
+	return ok(c, map[string]interface{}{
+		"acct_session_id": session.AcctSessionId,
+		"up_rate":         req.UpRate,
+		"down_rate":       req.DownRate,
+	})
+}
+
+// GetOnlineStats returns the recorded online session count and throughput samples
+// @Summary get online session statistics
+// @Tags OnlineSession
+// @Param hours query int false "Look-back window in hours (1-168, default 24)"
+// @Success 200 {array} domain.RadiusOnlineStat
+// @Router /api/v1/radius/online/stats [get]
+func GetOnlineStats(c echo.Context) error {
+	hours := 24
+	if value := strings.TrimSpace(c.QueryParam("hours")); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 168 {
+			return fail(c, http.StatusBadRequest, "INVALID_HOURS", "hours must be between 1 and 168", nil)
+		}
+		hours = parsed
+	}
+
+	var samples []domain.RadiusOnlineStat
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	if err := GetRequestDB(c).Where("sample_time >= ?", since).Order("sample_time ASC").Find(&samples).Error; err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to query online statistics", err.Error())
+	}
+
+	return ok(c, samples)
+}
+
 // registerSessionRoutes Register online session routes
 func registerSessionRoutes() {
 	webserver.ApiGET("/sessions", ListOnlineSessions)
 	webserver.ApiGET("/sessions/:id", GetOnlineSession)
-	webserver.ApiDELETE("/sessions/:id", DeleteOnlineSession)
+	webserver.ApiDELETE("/sessions/:id", DeleteOnlineSession, invalidatesCache(cacheDashboardStats, cacheOnlineStats))
+	webserver.ApiPOST("/radius/online/:session_id/rate", ChangeOnlineSessionRate)
+	webserver.ApiGET("/radius/online/stats", GetOnlineStats, cachedResponse(cacheOnlineStats))
 }
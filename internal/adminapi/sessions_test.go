@@ -1,18 +1,24 @@
 package adminapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors/mikrotik"
 	"gorm.io/gorm"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2866"
 )
 
 // createTestOnlineSession Create test online session data
@@ -710,3 +716,146 @@ func TestSessionsFilterCombinations(t *testing.T) {
 		})
 	}
 }
+
+func TestChangeOnlineSessionRate(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx := setupTestApp(t, db)
+
+	mikrotikNas := createTestNas(db, "mikrotik-nas", "192.168.5.1")
+	require.NoError(t, db.Model(mikrotikNas).Update("vendor_code", vendors.CodeMikrotik).Error)
+	createTestNas(db, "generic-nas", "192.168.5.2")
+
+	createTestOnlineSession(db, "mtuser", "192.168.5.1", "10.0.5.1")
+	createTestOnlineSession(db, "genericuser", "192.168.5.2", "10.0.5.2")
+
+	var sent *radius.Packet
+	var sentAddr string
+	originalExchange := coaExchange
+	coaExchange = func(_ context.Context, pkt *radius.Packet, addr string) (*radius.Packet, error) {
+		sent = pkt
+		sentAddr = addr
+		return pkt.Response(radius.CodeCoAACK), nil
+	}
+	defer func() { coaExchange = originalExchange }()
+
+	tests := []struct {
+		name           string
+		sessionID      string
+		body           string
+		expectedStatus int
+		expectedError  string
+		expectSent     bool
+	}{
+		{
+			name:           "Mikrotik session",
+			sessionID:      "session-mtuser",
+			body:           `{"up_rate":2048,"down_rate":4096}`,
+			expectedStatus: http.StatusOK,
+			expectSent:     true,
+		},
+		{
+			name:           "Generic vendor not supported",
+			sessionID:      "session-genericuser",
+			body:           `{"up_rate":2048,"down_rate":4096}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "UNSUPPORTED_VENDOR",
+		},
+		{
+			name:           "Missing session",
+			sessionID:      "session-nobody",
+			body:           `{"up_rate":2048,"down_rate":4096}`,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "NOT_FOUND",
+		},
+		{
+			name:           "Invalid rate",
+			sessionID:      "session-mtuser",
+			body:           `{"up_rate":0,"down_rate":4096}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent = nil
+			e := setupTestEcho()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/radius/online/"+tt.sessionID+"/rate", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := CreateTestContext(e, db, req, rec, appCtx)
+			c.SetParamNames("session_id")
+			c.SetParamValues(tt.sessionID)
+
+			err := ChangeOnlineSessionRate(c)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedError != "" {
+				var errResponse ErrorResponse
+				_ = json.Unmarshal(rec.Body.Bytes(), &errResponse)
+				assert.Equal(t, tt.expectedError, errResponse.Error)
+			}
+
+			if !tt.expectSent {
+				assert.Nil(t, sent)
+				return
+			}
+			require.NotNil(t, sent)
+			assert.Equal(t, radius.CodeCoARequest, sent.Code)
+			assert.Equal(t, "192.168.5.1:3799", sentAddr)
+			assert.Equal(t, tt.sessionID, rfc2866.AcctSessionID_GetString(sent))
+			assert.Equal(t, "2048k/4096k", mikrotik.MikrotikRateLimit_GetString(sent))
+		})
+	}
+}
+
+func TestGetOnlineStats(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx := setupTestApp(t, db)
+	require.NoError(t, db.AutoMigrate(&domain.RadiusOnlineStat{}))
+
+	now := time.Now()
+	require.NoError(t, db.Create(&[]domain.RadiusOnlineStat{
+		{ID: 1, SampleTime: now.Add(-48 * time.Hour), OnlineCount: 1},
+		{ID: 2, SampleTime: now.Add(-2 * time.Hour), OnlineCount: 5, InputRate: 800, OutputRate: 1600},
+		{ID: 3, SampleTime: now.Add(-time.Minute), OnlineCount: 7},
+	}).Error)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedCounts []int64
+	}{
+		{name: "Default window", expectedStatus: http.StatusOK, expectedCounts: []int64{5, 7}},
+		{name: "Narrow window", query: "?hours=1", expectedStatus: http.StatusOK, expectedCounts: []int64{7}},
+		{name: "Invalid window", query: "?hours=0", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := setupTestEcho()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/radius/online/stats"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := CreateTestContext(e, db, req, rec, appCtx)
+
+			require.NoError(t, GetOnlineStats(c))
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response Response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			dataBytes, _ := json.Marshal(response.Data)
+			var samples []domain.RadiusOnlineStat
+			require.NoError(t, json.Unmarshal(dataBytes, &samples))
+
+			counts := make([]int64, 0, len(samples))
+			for _, sample := range samples {
+				counts = append(counts, sample.OnlineCount)
+			}
+			assert.Equal(t, tt.expectedCounts, counts)
+		})
+	}
+}
@@ -28,17 +28,20 @@ type settingsPayload struct {
 // registerSettingsRoutes registers system setting routes
 func registerSettingsRoutes() {
 	webserver.ApiGET("/system/settings", listSettings)
+	webserver.ApiGET("/system/settings/schema", getSettingsSchema)
 	webserver.ApiGET("/system/settings/:id", getSettings)
 	webserver.ApiGET("/system/config/schemas", getConfigSchemas)
 	webserver.ApiPOST("/system/settings", createSettings)
 	webserver.ApiPUT("/system/settings/:id", updateSettings)
 	webserver.ApiDELETE("/system/settings/:id", deleteSettings)
 	webserver.ApiPOST("/system/config/reload", reloadConfig)
+	webserver.ApiPOST("/system/ensure-defaults", ensureDefaults)
+	webserver.ApiGET("/system/selfcheck", getSelfCheck)
 }
 
 // listSettings retrieves the system settings list
 func listSettings(c echo.Context) error {
-	page, pageSize := parsePagination(c)
+	page, pageSize := parsePagination(c, "pageSize", 20, 200)
 
 	base := GetDB(c).Model(&domain.SysConfig{})
 	base = applySettingsFilters(base, c)
@@ -83,9 +86,38 @@ func getConfigSchemas(c echo.Context) error {
 		return fail(c, http.StatusInternalServerError, "CONFIG_MANAGER_NOT_FOUND", "Configuration manager is not initialized", nil)
 	}
 
-	schemas := GetAppContext(c).ConfigMgr().GetAllSchemas()
+	return ok(c, configSchemaList(GetAppContext(c).ConfigMgr().GetAllSchemas()))
+}
+
+// settingsSchemaCategory groups the configuration schemas of one category
+type settingsSchemaCategory struct {
+	Category string                   `json:"category"`
+	Schemas  []map[string]interface{} `json:"schemas"`
+}
+
+// getSettingsSchema returns the configuration schemas grouped by category
+func getSettingsSchema(c echo.Context) error {
+	if GetAppContext(c).ConfigMgr() == nil {
+		return fail(c, http.StatusInternalServerError, "CONFIG_MANAGER_NOT_FOUND", "Configuration manager is not initialized", nil)
+	}
+
+	result := make([]settingsSchemaCategory, 0)
+	for _, schemaData := range configSchemaList(GetAppContext(c).ConfigMgr().GetAllSchemas()) {
+		key, _ := schemaData["key"].(string) //nolint:errcheck
+		category, name, _ := strings.Cut(key, ".")
+		schemaData["name"] = name
+
+		if n := len(result); n == 0 || result[n-1].Category != category {
+			result = append(result, settingsSchemaCategory{Category: category})
+		}
+		result[len(result)-1].Schemas = append(result[len(result)-1].Schemas, schemaData)
+	}
 
-	// Convert to a frontend-friendly format
+	return ok(c, result)
+}
+
+// configSchemaList converts schemas to a frontend-friendly format sorted by key
+func configSchemaList(schemas map[string]*app.ConfigSchema) []map[string]interface{} {
 	var result []map[string]interface{}
 	keys := make([]string, 0, len(schemas))
 	for key := range schemas {
@@ -124,7 +156,15 @@ func getConfigSchemas(c echo.Context) error {
 		result = append(result, schemaData)
 	}
 
-	return ok(c, result)
+	return result
+}
+
+// validateSettingValue checks a setting value against its registered schema
+func validateSettingValue(c echo.Context, settingType, name, value string) error {
+	if GetAppContext(c).ConfigMgr() == nil {
+		return nil
+	}
+	return GetAppContext(c).ConfigMgr().Validate(settingType, name, value)
 }
 
 // getConfigTypeName resolves configuration type names
@@ -159,6 +199,10 @@ func createSettings(c echo.Context) error {
 		return fail(c, http.StatusBadRequest, "INVALID_REQUEST", "type, name, and value cannot be empty", nil)
 	}
 
+	if err := validateSettingValue(c, payload.Type, payload.Name, payload.Value); err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_VALUE", "Setting value does not match its schema", err.Error())
+	}
+
 	// Check whether a setting with the same type and name already exists (unique constraint)
 	var exists int64
 	GetDB(c).Model(&domain.SysConfig{}).
@@ -228,6 +272,10 @@ func updateSettings(c echo.Context) error {
 	}
 	setting.UpdatedAt = time.Now()
 
+	if err := validateSettingValue(c, setting.Type, setting.Name, setting.Value); err != nil {
+		return fail(c, http.StatusBadRequest, "INVALID_VALUE", "Setting value does not match its schema", err.Error())
+	}
+
 	if err := GetDB(c).Save(&setting).Error; err != nil {
 		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to update system setting", err.Error())
 	}
@@ -284,3 +332,48 @@ func reloadConfig(c echo.Context) error {
 		"time":    time.Now(),
 	})
 }
+
+// ensureDefaults re-creates missing default data (super admin, settings and
+// the auto-registration node) and reports what was created or repaired
+func ensureDefaults(c echo.Context) error {
+	currentOpr, err := resolveOperatorFromContext(c)
+	if err != nil {
+		return fail(c, http.StatusUnauthorized, "UNAUTHORIZED", "Unable to retrieve current user information", nil)
+	}
+	if currentOpr.Level != "super" {
+		return fail(c, http.StatusForbidden, "PERMISSION_DENIED", "Only super admins can restore default data", nil)
+	}
+
+	report := GetAppContext(c).EnsureDefaults()
+	if len(report.Errors) > 0 {
+		return fail(c, http.StatusInternalServerError, "ENSURE_DEFAULTS_FAILED", "Failed to restore some default data", report)
+	}
+
+	return ok(c, report)
+}
+
+// selfCheckReport is the subsystem status returned by the self-check endpoint
+type selfCheckReport struct {
+	Healthy bool              `json:"healthy"`
+	Checks  []app.CheckResult `json:"checks"`
+}
+
+// getSelfCheck reports whether each subsystem initialized at startup and
+// its last error
+func getSelfCheck(c echo.Context) error {
+	currentOpr, err := resolveOperatorFromContext(c)
+	if err != nil {
+		return fail(c, http.StatusUnauthorized, "UNAUTHORIZED", "Unable to retrieve current user information", nil)
+	}
+	if currentOpr.Level != "super" {
+		return fail(c, http.StatusForbidden, "PERMISSION_DENIED", "Only super admins can run the self-check", nil)
+	}
+
+	report := selfCheckReport{Healthy: true, Checks: GetAppContext(c).SelfCheck()}
+	for _, check := range report.Checks {
+		if !check.OK {
+			report.Healthy = false
+		}
+	}
+	return ok(c, report)
+}
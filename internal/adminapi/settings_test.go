@@ -0,0 +1,190 @@
+package adminapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+)
+
+func TestCreateSettingsValidatesSchema(t *testing.T) {
+	db, e, appCtx := CreateTestAppContext(t)
+	// Start without the defaults seeded at application startup
+	require.NoError(t, db.Where("1 = 1").Delete(&domain.SysConfig{}).Error)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "Valid integer",
+			body:           `{"type":"radius","name":"AccountingHistoryDays","value":"30"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Out-of-type value",
+			body:           `{"type":"radius","name":"AcctInterimInterval","value":"often"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_VALUE",
+		},
+		{
+			name:           "Value outside enum",
+			body:           `{"type":"radius","name":"EapMethod","value":"eap-tls"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_VALUE",
+		},
+		{
+			name:           "Unregistered setting is accepted",
+			body:           `{"type":"custom","name":"Anything","value":"free text"}`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/system/settings", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := CreateTestContext(e, db, req, rec, appCtx)
+
+			require.NoError(t, createSettings(c))
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedError != "" {
+				var errResponse ErrorResponse
+				_ = json.Unmarshal(rec.Body.Bytes(), &errResponse) //nolint:errcheck
+				assert.Equal(t, tt.expectedError, errResponse.Error)
+			}
+		})
+	}
+}
+
+func TestUpdateSettingsValidatesSchema(t *testing.T) {
+	db, e, appCtx := CreateTestAppContext(t)
+	// Start without the defaults seeded at application startup
+	require.NoError(t, db.Where("1 = 1").Delete(&domain.SysConfig{}).Error)
+
+	setting := domain.SysConfig{ID: 1, Type: "radius", Name: "RejectDelayMaxRejects", Value: "7"}
+	require.NoError(t, db.Create(&setting).Error)
+
+	update := func(value string) int {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/system/settings/1", strings.NewReader(`{"value":"`+value+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.FormatInt(setting.ID, 10))
+		require.NoError(t, updateSettings(c))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, update("100000"))
+	assert.Equal(t, http.StatusOK, update("10"))
+
+	var stored domain.SysConfig
+	require.NoError(t, db.First(&stored, setting.ID).Error)
+	assert.Equal(t, "10", stored.Value)
+}
+
+func TestGetSettingsSchema(t *testing.T) {
+	db, e, appCtx := CreateTestAppContext(t)
+	// Start without the defaults seeded at application startup
+	require.NoError(t, db.Where("1 = 1").Delete(&domain.SysConfig{}).Error)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/system/settings/schema", nil)
+	rec := httptest.NewRecorder()
+	c := CreateTestContext(e, db, req, rec, appCtx)
+
+	require.NoError(t, getSettingsSchema(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	dataBytes, _ := json.Marshal(response.Data)
+	var categories []settingsSchemaCategory
+	require.NoError(t, json.Unmarshal(dataBytes, &categories))
+
+	byCategory := make(map[string][]map[string]interface{})
+	for _, category := range categories {
+		byCategory[category.Category] = category.Schemas
+	}
+	require.Contains(t, byCategory, "radius")
+
+	var found bool
+	for _, schema := range byCategory["radius"] {
+		if schema["name"] == "EapMethod" {
+			found = true
+			assert.Equal(t, "string", schema["type"])
+			assert.Equal(t, "eap-md5", schema["default"])
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestEnsureDefaults(t *testing.T) {
+	db, e, appCtx := CreateTestAppContext(t)
+
+	call := func(level string) (*httptest.ResponseRecorder, map[string][]string) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/system/ensure-defaults", nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		c.Set("current_operator", &domain.SysOpr{ID: 1, Username: "tester", Level: level, Status: "enabled"})
+		require.NoError(t, ensureDefaults(c))
+
+		var response Response
+		_ = json.Unmarshal(rec.Body.Bytes(), &response) //nolint:errcheck
+		dataBytes, _ := json.Marshal(response.Data)     //nolint:errcheck
+		var report map[string][]string
+		_ = json.Unmarshal(dataBytes, &report) //nolint:errcheck
+		return rec, report
+	}
+
+	// Application startup already seeded the defaults
+	rec, report := call("super")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, report["created"])
+	assert.Empty(t, report["repaired"])
+
+	require.NoError(t, db.Where("username = ?", "admin").Delete(&domain.SysOpr{}).Error)
+	rec, report = call("super")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"operator:admin"}, report["created"])
+
+	rec, _ = call("operator")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestGetSelfCheck(t *testing.T) {
+	db, e, appCtx := CreateTestAppContext(t)
+
+	call := func(level string) (*httptest.ResponseRecorder, selfCheckReport) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/system/selfcheck", nil)
+		rec := httptest.NewRecorder()
+		c := CreateTestContext(e, db, req, rec, appCtx)
+		c.Set("current_operator", &domain.SysOpr{ID: 1, Username: "tester", Level: level, Status: "enabled"})
+		require.NoError(t, getSelfCheck(c))
+
+		var response Response
+		_ = json.Unmarshal(rec.Body.Bytes(), &response) //nolint:errcheck
+		dataBytes, _ := json.Marshal(response.Data)     //nolint:errcheck
+		var report selfCheckReport
+		_ = json.Unmarshal(dataBytes, &report) //nolint:errcheck
+		return rec, report
+	}
+
+	rec, report := call("super")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, report.Healthy, "checks: %+v", report.Checks)
+	assert.NotEmpty(t, report.Checks)
+
+	rec, _ = call("operator")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -246,13 +246,14 @@ func (ur *UserUpdateRequest) toRadiusUser() *domain.RadiusUser {
 func registerUserRoutes() {
 	webserver.ApiGET("/users", listRadiusUsers)
 	webserver.ApiGET("/users/:id", getRadiusUser)
-	webserver.ApiPOST("/users", createRadiusUser)
-	webserver.ApiPUT("/users/:id", updateRadiusUser)
-	webserver.ApiDELETE("/users/:id", deleteRadiusUser)
+	webserver.ApiPOST("/users", createRadiusUser, invalidatesCache(cacheDashboardStats))
+	webserver.ApiPUT("/users/:id", updateRadiusUser, invalidatesCache(cacheDashboardStats))
+	webserver.ApiDELETE("/users/:id", deleteRadiusUser, invalidatesCache(cacheDashboardStats))
+	webserver.ApiGET("/radius/users/:username/effective-profile", getEffectiveProfile)
 }
 
 func listRadiusUsers(c echo.Context) error {
-	page, pageSize := parsePagination(c)
+	page, pageSize := parsePagination(c, "pageSize", 20, 200)
 
 	// Validate and sanitize sort field
 	sortField := c.QueryParam("sort")
@@ -567,6 +568,68 @@ func deleteRadiusUser(c echo.Context) error {
 	})
 }
 
+// EffectiveProfile is the resolved set of policy attributes the RADIUS engine
+// applies to a user after merging user overrides with the linked profile
+type EffectiveProfile struct {
+	Username        string `json:"username"`
+	ProfileID       int64  `json:"profile_id,string"`
+	ProfileName     string `json:"profile_name"`
+	ProfileLinkMode string `json:"profile_link_mode"`
+	UpRate          int    `json:"up_rate"`
+	DownRate        int    `json:"down_rate"`
+	ActiveNum       int    `json:"active_num"`
+	AddrPool        string `json:"addr_pool"`
+	Domain          string `json:"domain"`
+	IPv6PrefixPool  string `json:"ipv6_prefix_pool"`
+	BindMac         int    `json:"bind_mac"`
+	BindVlan        int    `json:"bind_vlan"`
+}
+
+// getEffectiveProfile returns the profile attributes as resolved through the
+// profile cache, mirroring what authentication would apply to the user
+func getEffectiveProfile(c echo.Context) error {
+	username := strings.TrimSpace(c.Param("username"))
+	if username == "" {
+		return fail(c, http.StatusBadRequest, "INVALID_USERNAME", "Username is required", nil)
+	}
+
+	var user domain.RadiusUser
+	if err := GetDB(c).Where("username = ?", username).First(&user).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return fail(c, http.StatusNotFound, "USER_NOT_FOUND", "User not found", nil)
+	} else if err != nil {
+		return fail(c, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to query users", err.Error())
+	}
+
+	cache := GetAppContext(c).ProfileCache()
+
+	linkMode := "static"
+	if user.ProfileLinkMode == domain.ProfileLinkModeDynamic {
+		linkMode = "dynamic"
+	}
+
+	result := EffectiveProfile{
+		Username:        user.Username,
+		ProfileID:       user.ProfileId,
+		ProfileLinkMode: linkMode,
+		UpRate:          user.GetUpRate(cache),
+		DownRate:        user.GetDownRate(cache),
+		ActiveNum:       user.GetActiveNum(cache),
+		AddrPool:        user.GetAddrPool(cache),
+		Domain:          user.GetDomain(cache),
+		IPv6PrefixPool:  user.GetIPv6PrefixPool(cache),
+		BindMac:         user.GetBindMac(cache),
+		BindVlan:        user.GetBindVlan(cache),
+	}
+
+	if user.ProfileId > 0 {
+		if profile, err := cache.Get(user.ProfileId); err == nil {
+			result.ProfileName = profile.Name
+		}
+	}
+
+	return ok(c, result)
+}
+
 func applyUserFilters(db *gorm.DB, c echo.Context) *gorm.DB {
 	if status := strings.TrimSpace(c.QueryParam("status")); status != "" {
 		db = db.Where("radius_user.status = ?", strings.ToLower(status))
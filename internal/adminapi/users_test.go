@@ -830,3 +830,102 @@ func TestUserEdgeCases(t *testing.T) {
 		assert.WithinDuration(t, expectedExpire, user.ExpireTime, time.Hour*24)
 	})
 }
+
+func TestGetEffectiveProfile(t *testing.T) {
+	db := setupTestDB(t)
+	appCtx := setupTestApp(t, db)
+
+	profile := createTestProfile(db, "effective-profile")
+
+	// Dynamic user without overrides, resolved through the profile cache
+	dynamicUser := createTestUser(db, "dynamicuser", profile.ID)
+	require.NoError(t, db.Model(dynamicUser).Updates(map[string]interface{}{
+		"profile_link_mode": domain.ProfileLinkModeDynamic,
+		"up_rate":           0,
+		"down_rate":         0,
+		"active_num":        0,
+		"addr_pool":         "",
+	}).Error)
+
+	// Cached profile differs from the stored row to prove the cache is used
+	cached := *profile
+	cached.UpRate = 4096
+	cached.DownRate = 8192
+	cached.ActiveNum = 3
+	appCtx.ProfileCache().Set(profile.ID, &cached)
+
+	// Static user keeps its own values
+	createTestUser(db, "staticuser", profile.ID)
+
+	tests := []struct {
+		name           string
+		username       string
+		expectedStatus int
+		expectedError  string
+		expectedMode   string
+		expectedUp     int
+		expectedDown   int
+		expectedActive int
+	}{
+		{
+			name:           "Dynamic user resolved via cache",
+			username:       "dynamicuser",
+			expectedStatus: http.StatusOK,
+			expectedMode:   "dynamic",
+			expectedUp:     4096,
+			expectedDown:   8192,
+			expectedActive: 3,
+		},
+		{
+			name:           "Static user uses stored values",
+			username:       "staticuser",
+			expectedStatus: http.StatusOK,
+			expectedMode:   "static",
+			expectedUp:     1024,
+			expectedDown:   2048,
+			expectedActive: 1,
+		},
+		{
+			name:           "Missing user",
+			username:       "nobody",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "USER_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := setupTestEcho()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/radius/users/"+tt.username+"/effective-profile", nil)
+			rec := httptest.NewRecorder()
+			c := CreateTestContext(e, db, req, rec, appCtx)
+			c.SetParamNames("username")
+			c.SetParamValues(tt.username)
+
+			err := getEffectiveProfile(c)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedStatus != http.StatusOK {
+				var errResponse ErrorResponse
+				_ = json.Unmarshal(rec.Body.Bytes(), &errResponse)
+				assert.Equal(t, tt.expectedError, errResponse.Error)
+				return
+			}
+
+			var response Response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			dataBytes, _ := json.Marshal(response.Data)
+			var result EffectiveProfile
+			require.NoError(t, json.Unmarshal(dataBytes, &result))
+
+			assert.Equal(t, tt.username, result.Username)
+			assert.Equal(t, profile.ID, result.ProfileID)
+			assert.Equal(t, "effective-profile", result.ProfileName)
+			assert.Equal(t, tt.expectedMode, result.ProfileLinkMode)
+			assert.Equal(t, tt.expectedUp, result.UpRate)
+			assert.Equal(t, tt.expectedDown, result.DownRate)
+			assert.Equal(t, tt.expectedActive, result.ActiveNum)
+		})
+	}
+}
@@ -1,11 +1,13 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path"
 	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 	_ "time/tzdata"
 
@@ -30,8 +32,15 @@ type Application struct {
 	configManager *ConfigManager
 	profileCache  *ProfileCache
 	qosService    interface{} // QoS sync service (initialized in initJob)
+	onlineStats   onlineStatsCollector
+
+	initMu     sync.Mutex
+	initStatus map[string]initRecord // Last initialization outcome by subsystem, see SelfCheck
 }
 
+// initMetrics initializes the metrics store, replaced in tests
+var initMetrics = metrics.InitMetrics
+
 // Ensure Application implements all interfaces
 var (
 	_ DBProvider            = (*Application)(nil)
@@ -66,6 +75,7 @@ func (a *Application) Init(cfg *config.AppConfig) {
 	} else {
 		time.Local = loc
 	}
+	a.recordInit(SubsystemTimezone, err)
 
 	// Initialize zap logger
 	var zapConfig zap.Config
@@ -115,10 +125,11 @@ func (a *Application) Init(cfg *config.AppConfig) {
 	zap.ReplaceGlobals(logger)
 
 	// Initialize metrics with workdir convention
-	err = metrics.InitMetrics(cfg.System.Workdir)
+	err = initMetrics(cfg.System.Workdir)
 	if err != nil {
 		zap.S().Warn("Failed to initialize metrics:", err)
 	}
+	a.recordInit(SubsystemMetrics, err)
 
 	// Initialize database connection
 	if cfg.Database.Type == "" {
@@ -150,24 +161,26 @@ func (a *Application) Init(cfg *config.AppConfig) {
 		zap.S().Errorf("database migration failed: %v", err)
 	}
 
-	// wait for database initialization to complete
-	go func() {
-		time.Sleep(3 * time.Second)
-		a.checkSuper()
-		a.checkSettings()
-		a.checkDefaultPNode()
-	}()
+	// Seed default data once the schema is in place, before configs are loaded
+	a.EnsureDefaults()
 
 	// Initialize the configuration manager
 	a.configManager = NewConfigManager(a)
+	a.recordInit(SubsystemConfig, nil)
 
 	// Initialize profile cache for dynamic profile linking
 	a.profileCache = NewProfileCache(a.gormDB, DefaultProfileCacheTTL)
+	a.recordInit(SubsystemProfileCache, nil)
 
 	a.initJob()
 }
 
 func (a *Application) MigrateDB(track bool) (err error) {
+	migrateMu.Lock()
+	defer migrateMu.Unlock()
+
+	// Failures are logged and not returned, SelfCheck reports the first one
+	var failure error
 	defer func() {
 		if err1 := recover(); err1 != nil {
 			if os.Getenv("GO_DEGUB_TRACE") != "" {
@@ -177,16 +190,28 @@ func (a *Application) MigrateDB(track bool) (err error) {
 			if ok {
 				err = err2
 				zap.S().Error(err2.Error())
+			} else {
+				err2 = fmt.Errorf("migration panic: %v", err1)
 			}
+			failure = err2
 		}
+		a.recordInit(SubsystemMigration, failure)
 	}()
 	if track {
 		if err := a.gormDB.Debug().Migrator().AutoMigrate(domain.Tables...); err != nil {
 			zap.S().Error(err)
+			failure = err
 		}
 	} else {
 		if err := a.gormDB.Migrator().AutoMigrate(domain.Tables...); err != nil {
 			zap.S().Error(err)
+			failure = err
+		}
+	}
+	if err := ensureNasIpaddrUniqueIndex(a.gormDB); err != nil {
+		zap.S().Error(err)
+		if failure == nil {
+			failure = err
 		}
 	}
 	return nil
@@ -226,15 +251,12 @@ func (a *Application) InitDb() {
 	if err != nil {
 		zap.S().Error(err)
 	}
+	if err := ensureNasIpaddrUniqueIndex(a.gormDB); err != nil {
+		zap.S().Error(err)
+	}
 
-	// Create default admin account
-	a.checkSuper()
-	
-	// Initialize default settings
-	a.checkSettings()
-	
-	// Create default node
-	a.checkDefaultPNode()
+	// Create the default admin account, settings and node
+	a.EnsureDefaults()
 
 	zap.S().Info("Database initialization completed successfully",
 		zap.String("namespace", "app"))
@@ -283,16 +305,23 @@ func (a *Application) GetQoSService() interface{} {
 }
 
 // checkDefaultPNode check default node
-func (a *Application) checkDefaultPNode() {
+func (a *Application) checkDefaultPNode(report *DefaultsReport) {
 	var pnode domain.NetNode
 	err := a.gormDB.Where("id=?", AutoRegisterPopNodeId).First(&pnode).Error
-	if err != nil {
-		a.gormDB.Create(&domain.NetNode{
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := a.gormDB.Create(&domain.NetNode{
 			ID:     AutoRegisterPopNodeId,
 			Name:   "default",
 			Tags:   "system",
 			Remark: "Device auto-registration node",
-		})
+		}).Error; err != nil {
+			report.fail("failed to create default node", err)
+			return
+		}
+		report.Created = append(report.Created, "node:default")
+	case err != nil:
+		report.fail("failed to query default node", err)
 	}
 }
 
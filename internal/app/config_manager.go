@@ -280,6 +280,18 @@ func (cm *ConfigManager) Set(category, name, value string) error {
 	return nil
 }
 
+// Validate checks a value against the schema registered for category.name.
+// Settings without a registered schema are accepted as-is.
+func (cm *ConfigManager) Validate(category, name, value string) error {
+	cm.mu.RLock()
+	schema, exists := cm.schemas[category+"."+name]
+	cm.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+	return cm.validate(schema, value)
+}
+
 // GetString retrieves a string configuration
 func (cm *ConfigManager) GetString(category, name string) string {
 	return cm.Get(category, name)
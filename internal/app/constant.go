@@ -15,6 +15,10 @@ const (
 	ConfigRadiusRejectDelayWindowSecond = "RejectDelayWindowSeconds"
 )
 
+// DefaultAcctInterimInterval is the interim interval in seconds used when
+// radius.AcctInterimInterval is not configured
+const DefaultAcctInterimInterval = 300
+
 var ConfigConstants = []string{
 	ConfigSystemTitle,
 	ConfigSystemTheme,
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// DBMaintenanceSchedule is when the db_maintenance task runs (daily at 03:30)
+const DBMaintenanceSchedule = "30 3 * * *"
+
+// dbMaintenanceStatements returns the maintenance SQL for a database dialect.
+// SQLite is always compacted; on Postgres VACUUM is opt-in because it is
+// expensive on large tables and autovacuum usually keeps up.
+func dbMaintenanceStatements(dialect string, vacuum bool) []string {
+	switch dialect {
+	case "sqlite":
+		return []string{"VACUUM", "ANALYZE"}
+	case "postgres":
+		if vacuum {
+			return []string{"VACUUM", "ANALYZE"}
+		}
+		return []string{"ANALYZE"}
+	default:
+		return nil
+	}
+}
+
+// SchedDBMaintenanceTask runs the db_maintenance task when it is enabled
+func (a *Application) SchedDBMaintenanceTask() {
+	defer func() {
+		if err := recover(); err != nil {
+			zap.S().Error(err)
+		}
+	}()
+
+	if !a.ConfigMgr().GetBool("system", "DBMaintenanceEnabled") {
+		return
+	}
+
+	if err := a.runDBMaintenance(a.ConfigMgr().GetBool("system", "DBMaintenanceVacuum")); err != nil {
+		zap.L().Error("db_maintenance task error", zap.Error(err))
+	}
+}
+
+// runDBMaintenance compacts and refreshes planner statistics of the database
+func (a *Application) runDBMaintenance(vacuum bool) error {
+	dialect := a.gormDB.Name()
+	statements := dbMaintenanceStatements(dialect, vacuum)
+	if len(statements) == 0 {
+		return fmt.Errorf("db maintenance not supported for %s", dialect)
+	}
+
+	sizeBefore, sizeErr := databaseSize(a.gormDB)
+	start := time.Now()
+	for _, stmt := range statements {
+		if err := a.gormDB.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("%s failed: %w", stmt, err)
+		}
+	}
+
+	fields := []zap.Field{
+		zap.String("dialect", dialect),
+		zap.Strings("statements", statements),
+		zap.Duration("duration", time.Since(start)),
+	}
+	if sizeErr == nil {
+		if sizeAfter, err := databaseSize(a.gormDB); err == nil {
+			fields = append(fields, zap.Int64("reclaimed_bytes", sizeBefore-sizeAfter))
+		}
+	}
+	zap.L().Info("db_maintenance completed", fields...)
+
+	return nil
+}
+
+// databaseSize returns the size in bytes of the current database
+func databaseSize(db *gorm.DB) (int64, error) {
+	var size int64
+	switch db.Name() {
+	case "sqlite":
+		err := db.Raw("SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()").Scan(&size).Error
+		return size, err
+	case "postgres":
+		err := db.Raw("SELECT pg_database_size(current_database())").Scan(&size).Error
+		return size, err
+	default:
+		return 0, fmt.Errorf("database size not supported for %s", db.Name())
+	}
+}
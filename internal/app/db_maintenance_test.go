@@ -0,0 +1,36 @@
+package app
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestDBMaintenanceStatements(t *testing.T) {
+	assert.Equal(t, []string{"VACUUM", "ANALYZE"}, dbMaintenanceStatements("sqlite", false))
+	assert.Equal(t, []string{"ANALYZE"}, dbMaintenanceStatements("postgres", false))
+	assert.Equal(t, []string{"VACUUM", "ANALYZE"}, dbMaintenanceStatements("postgres", true))
+	assert.Empty(t, dbMaintenanceStatements("mysql", true))
+}
+
+func TestRunDBMaintenanceIssuesSQLiteStatements(t *testing.T) {
+	app := newTestApplication(t)
+
+	var mu sync.Mutex
+	var executed []string
+	require.NoError(t, app.gormDB.Callback().Raw().After("gorm:raw").Register("test:capture_maintenance", func(tx *gorm.DB) {
+		mu.Lock()
+		defer mu.Unlock()
+		executed = append(executed, tx.Statement.SQL.String())
+	}))
+	defer app.gormDB.Callback().Raw().Remove("test:capture_maintenance") //nolint:errcheck
+
+	require.NoError(t, app.runDBMaintenance(false))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"VACUUM", "ANALYZE"}, executed)
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoIndexAdvice is returned for tables without known filter columns
+var ErrNoIndexAdvice = errors.New("no index advice for table")
+
+// filterColumn is a column the admin API and RADIUS services filter on
+type filterColumn struct {
+	Column string
+	Reason string
+}
+
+// indexAdvice lists the commonly filtered columns of the domain tables.
+// It is a hand-kept heuristic, not derived from query statistics.
+var indexAdvice = map[string][]filterColumn{
+	"radius_accounting": {
+		{"username", "accounting history of a user"},
+		{"nas_addr", "NAS top talkers and accounting filters"},
+		{"acct_session_id", "accounting lookups by session"},
+		{"acct_start_time", "date range reports and dashboard traffic"},
+	},
+	"radius_online": {
+		{"username", "concurrent session checks during authentication"},
+		{"nas_addr", "online sessions and dashboard of a NAS"},
+		{"acct_session_id", "accounting updates and session rate changes"},
+		{"framed_ipaddr", "online session filter by user IP"},
+		{"acct_start_time", "dashboard authentication counts"},
+	},
+	"radius_user": {
+		{"profile_id", "users of a profile"},
+		{"node_id", "users of a node"},
+		{"status", "user list filter and dashboard counts"},
+		{"expire_time", "expired user counts"},
+	},
+	"nas_qos": {
+		{"nas_id", "QoS queues of a NAS"},
+		{"user_id", "QoS queue of a user"},
+		{"status", "pending and failed QoS sync passes"},
+		{"remote_id", "QoS lookups by device queue ID"},
+	},
+	"nas_qos_log": {
+		{"qo_s_id", "sync history of a QoS queue"},
+		{"nas_id", "last QoS sync of a NAS"},
+	},
+	"radius_online_stat": {
+		{"sample_time", "online statistics charts"},
+	},
+}
+
+// IndexSuggestion is a missing index on a commonly filtered column
+type IndexSuggestion struct {
+	Table     string `json:"table"`
+	Column    string `json:"column"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+	Statement string `json:"statement"`
+}
+
+// SuggestIndexes reports the commonly filtered columns of the table that
+// do not lead any existing index, with the CREATE INDEX statement for each
+func SuggestIndexes(db *gorm.DB, table string) ([]IndexSuggestion, error) {
+	columns, found := indexAdvice[table]
+	if !found {
+		return nil, fmt.Errorf("%w %s", ErrNoIndexAdvice, table)
+	}
+
+	migrator := db.Migrator()
+	if !migrator.HasTable(table) {
+		return nil, fmt.Errorf("table %s does not exist", table)
+	}
+	indexes, err := migrator.GetIndexes(table)
+	if err != nil {
+		return nil, fmt.Errorf("read indexes of %s: %w", table, err)
+	}
+
+	// A column is covered when it is the first column of an index
+	covered := make(map[string]bool)
+	for _, idx := range indexes {
+		if cols := idx.Columns(); len(cols) > 0 {
+			covered[cols[0]] = true
+		}
+	}
+
+	suggestions := []IndexSuggestion{}
+	for _, col := range columns {
+		if covered[col.Column] || !migrator.HasColumn(table, col.Column) {
+			continue
+		}
+		name := fmt.Sprintf("idx_%s_%s", table, col.Column)
+		suggestions = append(suggestions, IndexSuggestion{
+			Table:     table,
+			Column:    col.Column,
+			Name:      name,
+			Reason:    col.Reason,
+			Statement: fmt.Sprintf("CREATE INDEX %s ON %s (%s)", name, table, col.Column),
+		})
+	}
+	return suggestions, nil
+}
@@ -12,7 +12,41 @@ import (
 	"gorm.io/gorm"
 )
 
-func (a *Application) checkSuper() {
+// DefaultsReport lists the default records touched by EnsureDefaults
+type DefaultsReport struct {
+	Created  []string `json:"created"`  // Missing records that were created
+	Repaired []string `json:"repaired"` // Existing records that were fixed in place
+	Errors   []string `json:"errors"`   // Steps that failed
+}
+
+func (r *DefaultsReport) fail(msg string, err error) {
+	zap.L().Error(msg, zap.Error(err))
+	r.Errors = append(r.Errors, msg+": "+err.Error())
+}
+
+// EnsureDefaults creates the default super admin, settings and node when they
+// are missing. Every step is idempotent, so it is safe to run again at any
+// time; running it on a complete database changes nothing.
+func (a *Application) EnsureDefaults() *DefaultsReport {
+	report := &DefaultsReport{
+		Created:  []string{},
+		Repaired: []string{},
+		Errors:   []string{},
+	}
+
+	a.checkSuper(report)
+	a.checkSettings(report)
+	a.checkDefaultPNode(report)
+
+	// Pick up settings created after the configuration manager loaded
+	if a.configManager != nil && len(report.Created) > 0 {
+		a.configManager.ReloadAll()
+	}
+
+	return report
+}
+
+func (a *Application) checkSuper(report *DefaultsReport) {
 	const superUsername = "admin"
 	const defaultPassword = "toughradius"
 
@@ -34,13 +68,14 @@ func (a *Application) checkSuper() {
 			Remark:    "super",
 			LastLogin: time.Now(),
 		}).Error; err != nil {
-			zap.L().Error("failed to create default super admin", zap.Error(err))
+			report.fail("failed to create default super admin", err)
 		} else {
 			zap.L().Info("initialized default super admin account", zap.String("username", superUsername))
+			report.Created = append(report.Created, "operator:"+superUsername)
 		}
 		return
 	case err != nil:
-		zap.L().Error("failed to query super admin", zap.Error(err))
+		report.fail("failed to query super admin", err)
 		return
 	}
 
@@ -66,9 +101,10 @@ func (a *Application) checkSuper() {
 	}
 
 	if err := a.gormDB.Model(&domain.SysOpr{}).Where("id = ?", operator.ID).Updates(updates).Error; err != nil {
-		zap.L().Error("failed to repair super admin account", zap.Error(err))
+		report.fail("failed to repair super admin account", err)
 		return
 	}
+	report.Repaired = append(report.Repaired, "operator:"+superUsername)
 
 	zap.L().Warn("repaired default super admin account",
 		zap.String("username", superUsername),
@@ -77,11 +113,11 @@ func (a *Application) checkSuper() {
 		zap.Bool("statusEnabled", resetStatus))
 }
 
-func (a *Application) checkSettings() {
+func (a *Application) checkSettings(report *DefaultsReport) {
 	// Load configuration definitions from the embedded JSON file
 	var schemasData ConfigSchemasJSON
 	if err := json.Unmarshal(configSchemasData, &schemasData); err != nil {
-		zap.L().Error("failed to load config schemas from JSON", zap.Error(err))
+		report.fail("failed to load config schemas from JSON", err)
 		return
 	}
 
@@ -99,23 +135,30 @@ func (a *Application) checkSettings() {
 
 		// Check whether the configuration already exists
 		var count int64
-		a.gormDB.Model(&domain.SysConfig{}).
+		if err := a.gormDB.Model(&domain.SysConfig{}).
 			Where("type = ? and name = ?", category, name).
-			Count(&count)
+			Count(&count).Error; err != nil {
+			report.fail("failed to query config "+schema.Key, err)
+			continue
+		}
 
 		// e.g., if the configuration does not exist, create the default configuration
 		if count == 0 {
-			a.gormDB.Create(&domain.SysConfig{
+			if err := a.gormDB.Create(&domain.SysConfig{
 				ID:     0,
 				Sort:   sortid,
 				Type:   category,
 				Name:   name,
 				Value:  schema.Default,
 				Remark: schema.Description,
-			})
+			}).Error; err != nil {
+				report.fail("failed to initialize config "+schema.Key, err)
+				continue
+			}
 			zap.L().Info("initialized config",
 				zap.String("key", schema.Key),
 				zap.String("default", schema.Default))
+			report.Created = append(report.Created, "setting:"+schema.Key)
 		}
 	}
 }
@@ -25,7 +25,7 @@ func newTestApplication(t *testing.T) *Application {
 func TestCheckSuperCreatesDefaultAdmin(t *testing.T) {
 	app := newTestApplication(t)
 
-	app.checkSuper()
+	app.checkSuper(&DefaultsReport{})
 
 	var admin domain.SysOpr
 	err := app.gormDB.Where("username = ?", "admin").First(&admin).Error
@@ -48,7 +48,7 @@ func TestCheckSuperRepairsExistingAdmin(t *testing.T) {
 	}
 	require.NoError(t, app.gormDB.Create(broken).Error)
 
-	app.checkSuper()
+	app.checkSuper(&DefaultsReport{})
 
 	var admin domain.SysOpr
 	err := app.gormDB.Where("username = ?", "admin").First(&admin).Error
@@ -58,3 +58,39 @@ func TestCheckSuperRepairsExistingAdmin(t *testing.T) {
 	assert.Equal(t, common.ENABLED, admin.Status)
 	assert.Equal(t, common.Sha256HashWithSalt("toughradius", common.GetSecretSalt()), admin.Password)
 }
+
+func TestEnsureDefaultsIsIdempotent(t *testing.T) {
+	app := newTestApplication(t)
+	for _, model := range []interface{}{&domain.SysOpr{}, &domain.SysConfig{}, &domain.NetNode{}} {
+		require.NoError(t, app.gormDB.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(model).Error)
+	}
+
+	first := app.EnsureDefaults()
+	assert.Empty(t, first.Errors)
+	assert.Contains(t, first.Created, "operator:admin")
+	assert.Contains(t, first.Created, "node:default")
+	assert.Contains(t, first.Created, "setting:radius.EapMethod")
+
+	counts := func() (operators, settings, nodes int64) {
+		app.gormDB.Model(&domain.SysOpr{}).Count(&operators)
+		app.gormDB.Model(&domain.SysConfig{}).Count(&settings)
+		app.gormDB.Model(&domain.NetNode{}).Count(&nodes)
+		return
+	}
+	operators, settings, nodes := counts()
+
+	// Re-running on a complete database creates nothing new
+	second := app.EnsureDefaults()
+	assert.Empty(t, second.Created)
+	assert.Empty(t, second.Repaired)
+	assert.Empty(t, second.Errors)
+	o, s, n := counts()
+	assert.Equal(t, operators, o)
+	assert.Equal(t, settings, s)
+	assert.Equal(t, nodes, n)
+
+	// A deleted default is restored on its own
+	require.NoError(t, app.gormDB.Where("type = ? AND name = ?", "radius", "EapMethod").Delete(&domain.SysConfig{}).Error)
+	third := app.EnsureDefaults()
+	assert.Equal(t, []string{"setting:radius.EapMethod"}, third.Created)
+}
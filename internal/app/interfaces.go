@@ -59,4 +59,6 @@ type AppContext interface {
 	MigrateDB(track bool) error
 	InitDb()
 	DropAll()
+	EnsureDefaults() *DefaultsReport
+	SelfCheck() []CheckResult
 }
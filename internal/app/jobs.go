@@ -2,6 +2,8 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/shirou/gopsutil/v4/process"
 	"github.com/talkincode/toughradius/v9/internal/domain"
 	"github.com/talkincode/toughradius/v9/internal/radiusd/qos"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/qos/clients"
 	"github.com/talkincode/toughradius/v9/pkg/metrics"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -64,12 +67,26 @@ func (a *Application) initJob() {
 	a.initQoSService()
 
 	var err error
+	var jobErrs []error
 	_, err = a.sched.AddFunc("@every 30s", func() {
 		go a.SchedSystemMonitorTask()
 		go a.SchedProcessMonitorTask()
 	})
 	if err != nil {
 		zap.S().Errorf("init job error %s", err.Error())
+		jobErrs = append(jobErrs, err)
+	}
+
+	_, err = a.sched.AddFunc("@every "+OnlineStatsInterval.String(), a.SchedOnlineStatsTask)
+	if err != nil {
+		zap.S().Errorf("init job error %s", err.Error())
+		jobErrs = append(jobErrs, err)
+	}
+
+	_, err = a.sched.AddFunc(DBMaintenanceSchedule, a.SchedDBMaintenanceTask)
+	if err != nil {
+		zap.S().Errorf("init job error %s", err.Error())
+		jobErrs = append(jobErrs, err)
 	}
 
 	_, err = a.sched.AddFunc("@daily", func() {
@@ -80,9 +97,11 @@ func (a *Application) initJob() {
 
 	if err != nil {
 		zap.S().Errorf("init job error %s", err.Error())
+		jobErrs = append(jobErrs, err)
 	}
 
 	a.sched.Start()
+	a.recordInit(SubsystemScheduler, errors.Join(jobErrs...))
 }
 
 // SchedSystemMonitorTask system monitor
@@ -158,6 +177,7 @@ func (a *Application) initQoSService() {
 	defer func() {
 		if err := recover(); err != nil {
 			zap.S().Error("QoS service initialization panic:", err)
+			a.recordInit(SubsystemQoS, fmt.Errorf("initialization panic: %v", err))
 		}
 	}()
 
@@ -173,6 +193,17 @@ func (a *Application) initQoSService() {
 	// Create and initialize service
 	zap.L().Debug("Creating NasQoSService instance", zap.String("namespace", "qos"))
 	qosService := qos.NewNasQoSService(a.gormDB, qosRepo, logRepo, nasRepo, userRepo)
+	qosService.SetBacklogAlertConfig(func() (int64, time.Duration) {
+		threshold := a.ConfigMgr().GetInt("qos", "BacklogAlertThreshold")
+		minutes := a.ConfigMgr().GetInt("qos", "BacklogAlertMinutes")
+		return threshold, time.Duration(minutes) * time.Minute
+	})
+	qosService.SetSyncPaused(func() bool {
+		return a.ConfigMgr().GetBool("qos", "SyncPaused")
+	})
+	clients.RouterOSSessions().SetLimit(func() int {
+		return int(a.ConfigMgr().GetInt("qos", "RouterOSMaxSessions"))
+	})
 
 	// Start sync background process
 	// Default sync interval: 1 minute
@@ -181,6 +212,7 @@ func (a *Application) initQoSService() {
 
 	// Store reference for graceful shutdown
 	a.qosService = qosService
+	a.recordInit(SubsystemQoS, nil)
 
 	zap.L().Info("✅ QoS sync service initialized successfully", zap.String("namespace", "qos"))
 }
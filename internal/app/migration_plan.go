@@ -0,0 +1,112 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"gorm.io/gorm"
+)
+
+// Kinds of schema objects reported by a migration plan
+const (
+	MigrationKindTable  = "table"
+	MigrationKindColumn = "column"
+	MigrationKindIndex  = "index"
+)
+
+// ErrMigrationInProgress is returned when a migration is already running
+var ErrMigrationInProgress = errors.New("database migration already in progress")
+
+// migrateMu serializes schema migrations of the process
+var migrateMu sync.Mutex
+
+// MigrationChange is a schema object AutoMigrate would add
+type MigrationChange struct {
+	Kind   string `json:"kind"`
+	Table  string `json:"table"`
+	Name   string `json:"name"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// MigrationPlan lists the schema changes pending for the models
+type MigrationPlan struct {
+	Pending bool              `json:"pending"`
+	Changes []MigrationChange `json:"changes"`
+}
+
+// PlanMigration compares models against the live schema and reports the
+// missing tables, columns and indexes that AutoMigrate would create.
+// Changes to existing columns (type, size, defaults) are not reported.
+func PlanMigration(db *gorm.DB, models ...interface{}) (*MigrationPlan, error) {
+	plan := &MigrationPlan{Changes: []MigrationChange{}}
+	migrator := db.Migrator()
+
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("parse model %T: %w", model, err)
+		}
+		table := stmt.Schema.Table
+
+		if !migrator.HasTable(model) {
+			plan.Changes = append(plan.Changes, MigrationChange{Kind: MigrationKindTable, Table: table, Name: table})
+			continue
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			if !migrator.HasColumn(model, field.DBName) {
+				plan.Changes = append(plan.Changes, MigrationChange{
+					Kind:   MigrationKindColumn,
+					Table:  table,
+					Name:   field.DBName,
+					Detail: migrator.FullDataTypeOf(field).SQL,
+				})
+			}
+		}
+
+		for _, idx := range stmt.Schema.ParseIndexes() {
+			if !migrator.HasIndex(model, idx.Name) {
+				plan.Changes = append(plan.Changes, MigrationChange{Kind: MigrationKindIndex, Table: table, Name: idx.Name})
+			}
+		}
+	}
+
+	plan.Pending = len(plan.Changes) > 0
+	return plan, nil
+}
+
+// PlanDBMigration reports the pending schema changes of the application
+// tables, including the NAS address unique index created after AutoMigrate
+func PlanDBMigration(db *gorm.DB) (*MigrationPlan, error) {
+	plan, err := PlanMigration(db, domain.Tables...)
+	if err != nil {
+		return nil, err
+	}
+
+	nas := &domain.NetNas{}
+	if db.Migrator().HasTable(nas) && !db.Migrator().HasIndex(nas, NasIpaddrUniqueIndex) {
+		plan.Changes = append(plan.Changes, MigrationChange{Kind: MigrationKindIndex, Table: nas.TableName(), Name: NasIpaddrUniqueIndex})
+		plan.Pending = true
+	}
+	return plan, nil
+}
+
+// ApplyDBMigration migrates the application tables and returns the first
+// error. It fails with ErrMigrationInProgress instead of waiting when
+// another migration is running.
+func ApplyDBMigration(db *gorm.DB) error {
+	if !migrateMu.TryLock() {
+		return ErrMigrationInProgress
+	}
+	defer migrateMu.Unlock()
+
+	if err := db.Migrator().AutoMigrate(domain.Tables...); err != nil {
+		return err
+	}
+	return ensureNasIpaddrUniqueIndex(db)
+}
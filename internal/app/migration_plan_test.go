@@ -0,0 +1,78 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"gorm.io/gorm"
+)
+
+// planDeviceV1 is the deployed schema of planDevice
+type planDeviceV1 struct {
+	ID   int64 `gorm:"primaryKey"`
+	Name string
+}
+
+func (planDeviceV1) TableName() string { return "plan_device" }
+
+// planDevice adds an indexed column the live schema does not have yet
+type planDevice struct {
+	ID     int64 `gorm:"primaryKey"`
+	Name   string
+	Serial string `gorm:"size:64;index"`
+}
+
+func (planDevice) TableName() string { return "plan_device" }
+
+type planLog struct {
+	ID int64 `gorm:"primaryKey"`
+}
+
+func (planLog) TableName() string { return "plan_log" }
+
+func TestPlanMigration(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&planDeviceV1{}))
+
+	plan, err := PlanMigration(db, &planDevice{}, &planLog{})
+	require.NoError(t, err)
+	assert.True(t, plan.Pending)
+	require.Len(t, plan.Changes, 3)
+	assert.Equal(t, MigrationKindColumn, plan.Changes[0].Kind)
+	assert.Equal(t, "serial", plan.Changes[0].Name)
+	assert.NotEmpty(t, plan.Changes[0].Detail, "column type is reported")
+	assert.Equal(t, MigrationChange{Kind: MigrationKindIndex, Table: "plan_device", Name: "idx_plan_device_serial"}, plan.Changes[1])
+	assert.Equal(t, MigrationChange{Kind: MigrationKindTable, Table: "plan_log", Name: "plan_log"}, plan.Changes[2])
+
+	// Planning changes nothing
+	assert.False(t, db.Migrator().HasColumn(&planDevice{}, "serial"))
+
+	require.NoError(t, db.AutoMigrate(&planDevice{}, &planLog{}))
+	plan, err = PlanMigration(db, &planDevice{}, &planLog{})
+	require.NoError(t, err)
+	assert.False(t, plan.Pending)
+	assert.Empty(t, plan.Changes)
+}
+
+func TestApplyDBMigration(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	plan, err := PlanDBMigration(db)
+	require.NoError(t, err)
+	assert.Len(t, plan.Changes, len(domain.Tables))
+
+	// A running migration is not waited for
+	migrateMu.Lock()
+	assert.ErrorIs(t, ApplyDBMigration(db), ErrMigrationInProgress)
+	migrateMu.Unlock()
+
+	require.NoError(t, ApplyDBMigration(db))
+	plan, err = PlanDBMigration(db)
+	require.NoError(t, err)
+	assert.False(t, plan.Pending, "unexpected changes: %+v", plan.Changes)
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// NasIpaddrUniqueIndex is the unique index keeping NAS IP addresses distinct,
+// since RADIUS requests are matched to a NAS by source IP
+const NasIpaddrUniqueIndex = "uidx_net_nas_ipaddr"
+
+// DuplicateNasIpaddrs returns the IP addresses shared by more than one NAS
+func DuplicateNasIpaddrs(db *gorm.DB) ([]string, error) {
+	var ipaddrs []string
+	err := db.Model(&domain.NetNas{}).
+		Group("ipaddr").
+		Having("COUNT(*) > 1").
+		Order("ipaddr").
+		Pluck("ipaddr", &ipaddrs).Error
+	return ipaddrs, err
+}
+
+// ensureNasIpaddrUniqueIndex adds the NAS IP unique index. While duplicate IPs
+// exist the index cannot be built; the duplicates are logged and the index is
+// retried on the next migration once they have been resolved.
+func ensureNasIpaddrUniqueIndex(db *gorm.DB) error {
+	if db.Migrator().HasIndex(&domain.NetNas{}, NasIpaddrUniqueIndex) {
+		return nil
+	}
+
+	duplicates, err := DuplicateNasIpaddrs(db)
+	if err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		zap.L().Warn("duplicate NAS IP addresses found, unique index not created",
+			zap.Strings("ipaddrs", duplicates),
+			zap.String("index", NasIpaddrUniqueIndex),
+		)
+		return nil
+	}
+
+	return db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS " + NasIpaddrUniqueIndex + " ON net_nas (ipaddr)").Error
+}
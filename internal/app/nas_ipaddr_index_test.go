@@ -0,0 +1,37 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"gorm.io/gorm"
+)
+
+func TestEnsureNasIpaddrUniqueIndex(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.NetNas{}))
+
+	require.NoError(t, db.Create(&domain.NetNas{ID: 1, Name: "nas-a", Ipaddr: "10.0.0.1"}).Error)
+	require.NoError(t, db.Create(&domain.NetNas{ID: 2, Name: "nas-b", Ipaddr: "10.0.0.1"}).Error)
+	require.NoError(t, db.Create(&domain.NetNas{ID: 3, Name: "nas-c", Ipaddr: "10.0.0.3"}).Error)
+
+	// Existing duplicates are reported and the index is postponed
+	duplicates, err := DuplicateNasIpaddrs(db)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1"}, duplicates)
+	require.NoError(t, ensureNasIpaddrUniqueIndex(db))
+	assert.False(t, db.Migrator().HasIndex(&domain.NetNas{}, NasIpaddrUniqueIndex))
+
+	// Once resolved the index is created and new duplicates are rejected
+	require.NoError(t, db.Delete(&domain.NetNas{}, 2).Error)
+	require.NoError(t, ensureNasIpaddrUniqueIndex(db))
+	assert.True(t, db.Migrator().HasIndex(&domain.NetNas{}, NasIpaddrUniqueIndex))
+	require.NoError(t, ensureNasIpaddrUniqueIndex(db))
+
+	err = db.Create(&domain.NetNas{ID: 4, Name: "nas-d", Ipaddr: "10.0.0.3"}).Error
+	assert.Error(t, err)
+}
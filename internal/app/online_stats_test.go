@@ -0,0 +1,56 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+)
+
+func TestCollectOnlineStatsRecordsSamplePerRun(t *testing.T) {
+	app := newTestApplication(t)
+	db := app.gormDB
+	require.NoError(t, db.Where("1 = 1").Delete(&domain.RadiusOnline{}).Error)
+	require.NoError(t, db.Where("1 = 1").Delete(&domain.RadiusOnlineStat{}).Error)
+
+	require.NoError(t, db.Create(&domain.RadiusOnline{
+		ID: 1, Username: "alice", AcctSessionId: "s1", AcctInputTotal: 1000, AcctOutputTotal: 5000,
+	}).Error)
+	require.NoError(t, db.Create(&domain.RadiusOnline{
+		ID: 2, Username: "bob", AcctSessionId: "s2", AcctInputTotal: 2000, AcctOutputTotal: 2000,
+	}).Error)
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// First run has no previous totals, so only the count is known
+	first, err := app.collectOnlineStats(start)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), first.OnlineCount)
+	assert.Zero(t, first.InputRate)
+	assert.Zero(t, first.OutputRate)
+
+	// Interim updates grow the totals; a new session carries no delta yet
+	require.NoError(t, db.Model(&domain.RadiusOnline{}).Where("id = ?", 1).
+		Updates(map[string]interface{}{"acct_input_total": 7000, "acct_output_total": 35000}).Error)
+	require.NoError(t, db.Create(&domain.RadiusOnline{
+		ID: 3, Username: "carol", AcctSessionId: "s3", AcctInputTotal: 900000, AcctOutputTotal: 900000,
+	}).Error)
+
+	second, err := app.collectOnlineStats(start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), second.OnlineCount)
+	assert.Equal(t, int64(6000*8/60), second.InputRate)
+	assert.Equal(t, int64(30000*8/60), second.OutputRate)
+
+	var count int64
+	require.NoError(t, db.Model(&domain.RadiusOnlineStat{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	// Samples older than the retention window are removed
+	_, err = app.collectOnlineStats(start.Add(OnlineStatsRetention + 30*time.Second))
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&domain.RadiusOnlineStat{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"errors"
+	"time"
+)
+
+// Subsystems reported by the startup self-check
+const (
+	SubsystemTimezone     = "timezone"
+	SubsystemMetrics      = "metrics"
+	SubsystemDatabase     = "database"
+	SubsystemMigration    = "migration"
+	SubsystemConfig       = "config"
+	SubsystemProfileCache = "profile_cache"
+	SubsystemQoS          = "qos"
+	SubsystemScheduler    = "scheduler"
+)
+
+var errNotInitialized = errors.New("not initialized")
+
+// CheckResult is the initialization status of one subsystem
+type CheckResult struct {
+	Name          string     `json:"name"`
+	OK            bool       `json:"ok"`
+	Error         string     `json:"error,omitempty"`
+	InitializedAt *time.Time `json:"initialized_at,omitempty"`
+}
+
+// initRecord is the outcome of the last initialization of a subsystem
+type initRecord struct {
+	err error
+	at  time.Time
+}
+
+// recordInit keeps the outcome of a subsystem initialization for SelfCheck,
+// err is nil when the subsystem started
+func (a *Application) recordInit(name string, err error) {
+	a.initMu.Lock()
+	defer a.initMu.Unlock()
+	if a.initStatus == nil {
+		a.initStatus = make(map[string]initRecord)
+	}
+	a.initStatus[name] = initRecord{err: err, at: time.Now()}
+}
+
+// SelfCheck reports the initialization status of each subsystem and its
+// last error. Init logs and carries on past most failures, so this is
+// where they surface. The database is pinged on every call.
+func (a *Application) SelfCheck() []CheckResult {
+	a.initMu.Lock()
+	status := make(map[string]initRecord, len(a.initStatus))
+	for name, record := range a.initStatus {
+		status[name] = record
+	}
+	a.initMu.Unlock()
+
+	recorded := func(name string, started bool) CheckResult {
+		record, found := status[name]
+		result := CheckResult{Name: name}
+		if found {
+			result.InitializedAt = &record.at
+		}
+		switch {
+		case found && record.err != nil:
+			result.Error = record.err.Error()
+		case !found || !started:
+			result.Error = errNotInitialized.Error()
+		default:
+			result.OK = true
+		}
+		return result
+	}
+
+	database := CheckResult{Name: SubsystemDatabase}
+	if err := a.pingDB(); err != nil {
+		database.Error = err.Error()
+	} else {
+		database.OK = true
+	}
+
+	return []CheckResult{
+		recorded(SubsystemTimezone, true),
+		recorded(SubsystemMetrics, true),
+		database,
+		recorded(SubsystemMigration, true),
+		recorded(SubsystemConfig, a.configManager != nil),
+		recorded(SubsystemProfileCache, a.profileCache != nil),
+		recorded(SubsystemQoS, a.qosService != nil),
+		recorded(SubsystemScheduler, a.sched != nil),
+	}
+}
+
+func (a *Application) pingDB() error {
+	if a.gormDB == nil {
+		return errNotInitialized
+	}
+	sqlDB, err := a.gormDB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Ping()
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/config"
+)
+
+func TestSelfCheck(t *testing.T) {
+	original := initMetrics
+	initMetrics = func(string) error { return errors.New("metrics store unavailable") }
+	t.Cleanup(func() { initMetrics = original })
+
+	cfg := &config.AppConfig{
+		System:   config.SysConfig{Location: "UTC", Workdir: t.TempDir()},
+		Database: config.DBConfig{Type: "sqlite", Name: ":memory:"},
+	}
+	a := NewApplication(cfg)
+	a.Init(cfg)
+	t.Cleanup(a.Release)
+
+	checks := make(map[string]CheckResult)
+	for _, check := range a.SelfCheck() {
+		checks[check.Name] = check
+	}
+
+	require.Contains(t, checks, SubsystemMetrics)
+	assert.False(t, checks[SubsystemMetrics].OK)
+	assert.Equal(t, "metrics store unavailable", checks[SubsystemMetrics].Error)
+	assert.NotNil(t, checks[SubsystemMetrics].InitializedAt)
+
+	for _, name := range []string{
+		SubsystemTimezone, SubsystemDatabase, SubsystemMigration, SubsystemConfig,
+		SubsystemProfileCache, SubsystemQoS, SubsystemScheduler,
+	} {
+		assert.True(t, checks[name].OK, "%s: %s", name, checks[name].Error)
+	}
+}
+
+func TestSelfCheckBeforeInit(t *testing.T) {
+	a := NewApplication(&config.AppConfig{})
+	for _, check := range a.SelfCheck() {
+		assert.False(t, check.OK, check.Name)
+		assert.Equal(t, errNotInitialized.Error(), check.Error, check.Name)
+	}
+}
@@ -21,31 +21,32 @@ func (NetNode) TableName() string {
 
 // NetNas NAS device data model, typically gateway-type devices, can be used as BRAS equipment
 type NetNas struct {
-	ID         int64     `json:"id,string" form:"id"`            // Primary key ID
-	NodeId     int64     `json:"node_id,string" form:"node_id"`  // Node ID
-	Name       string    `json:"name" form:"name"`               // Device name
-	Identifier string    `json:"identifier" form:"identifier"`   // Device identifier - RADIUS
-	Hostname   string    `json:"hostname" form:"hostname"`       // Device host address
-	Ipaddr     string    `json:"ipaddr" form:"ipaddr"`           // Device IP
-	Secret     string    `json:"secret" form:"secret"`           // Device RADIUS Secret
-	CoaPort    int       `json:"coa_port" form:"coa_port"`       // Device RADIUS COA Port
-	Model      string    `json:"model" form:"model"`             // Device model
-	VendorCode string    `json:"vendor_code" form:"vendor_code"` // Device vendor code
-	Status     string    `json:"status" form:"status"`           // Device status
-	Tags       string    `json:"tags" form:"tags"`               // Tags
-	Remark     string    `json:"remark" form:"remark"`           // Remark
+	ID              int64  `json:"id,string" form:"id"`                        // Primary key ID
+	NodeId          int64  `json:"node_id,string" form:"node_id"`              // Node ID
+	Name            string `json:"name" form:"name"`                           // Device name
+	Identifier      string `json:"identifier" form:"identifier"`               // Device identifier - RADIUS
+	Hostname        string `json:"hostname" form:"hostname"`                   // Device host address
+	Ipaddr          string `json:"ipaddr" form:"ipaddr"`                       // Device IP
+	Secret          string `json:"secret" form:"secret"`                       // Device RADIUS Secret
+	CoaPort         int    `json:"coa_port" form:"coa_port"`                   // Device RADIUS COA Port
+	MacAuthDisabled bool   `json:"mac_auth_disabled" form:"mac_auth_disabled"` // Reject MAC authentication (username equals the calling MAC) from this device
+	Model           string `json:"model" form:"model"`                         // Device model
+	VendorCode      string `json:"vendor_code" form:"vendor_code"`             // Device vendor code
+	Status          string `json:"status" form:"status"`                       // Device status
+	Tags            string `json:"tags" form:"tags"`                           // Tags
+	Remark          string `json:"remark" form:"remark"`                       // Remark
 	// QoS Management Fields
-	QoSEnabled    bool   `json:"qos_enabled" form:"qos_enabled"`       // Enable QoS management
-	QoSMethod     string `json:"qos_method" form:"qos_method"`         // "api" | "snmp"
-	QoSConfig     string `json:"qos_config" form:"qos_config"`         // JSON config for connection
-	APIHost       string `json:"api_host" form:"api_host"`             // API host/IP
-	APIPort       int    `json:"api_port" form:"api_port"`             // API port
-	APIUsername   string `json:"api_username" form:"api_username"`     // API username
-	APIPassword   string `json:"api_password" form:"api_password"`     // API password (encrypted)
-	SNMPVersion   string `json:"snmp_version" form:"snmp_version"`     // "v2c" | "v3"
-	SNMPCommunity string `json:"snmp_community" form:"snmp_community"` // SNMP community
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	QoSEnabled    bool      `json:"qos_enabled" form:"qos_enabled"`       // Enable QoS management
+	QoSMethod     string    `json:"qos_method" form:"qos_method"`         // "api" | "snmp"
+	QoSConfig     string    `json:"qos_config" form:"qos_config"`         // JSON config for connection
+	APIHost       string    `json:"api_host" form:"api_host"`             // API host/IP
+	APIPort       int       `json:"api_port" form:"api_port"`             // API port
+	APIUsername   string    `json:"api_username" form:"api_username"`     // API username
+	APIPassword   string    `json:"api_password" form:"api_password"`     // API password (encrypted)
+	SNMPVersion   string    `json:"snmp_version" form:"snmp_version"`     // "v2c" | "v3"
+	SNMPCommunity string    `json:"snmp_community" form:"snmp_community"` // SNMP community
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 // TableName Specify table name
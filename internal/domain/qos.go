@@ -5,24 +5,28 @@ import "time"
 // NasQoS Generic QoS configuration for NAS devices
 // Supports multiple vendors (Mikrotik, Huawei, H3C, etc) with different methods (API, SNMP)
 type NasQoS struct {
-	ID            int64     `json:"id,string" gorm:"primaryKey"`      // Primary key ID
-	UserID        int64     `json:"user_id,string" gorm:"index"`      // User ID
-	NasID         int64     `json:"nas_id,string" gorm:"index"`       // NAS device ID
-	NasAddr       string    `json:"nas_addr"`                         // NAS IP address
-	VendorCode    string    `json:"vendor_code" gorm:"index"`         // Vendor code (14988=Mikrotik, 2011=Huawei, etc)
-	QoSName       string    `json:"qos_name"`                         // Queue/Profile name in device
-	QoSType       string    `json:"qos_type"`                         // Type: "simple_queue", "tree", "policy", "profile"
-	UpRate        int       `json:"up_rate"`                          // Upload rate in Kbps
-	DownRate      int       `json:"down_rate"`                        // Download rate in Kbps
-	Method        string    `json:"method"`                           // Communication method: "api", "snmp", "cli"
-	RemoteID      string    `json:"remote_id"`                        // Queue/Profile ID in remote device
-	RemoteConfig  string    `json:"remote_config"`                    // JSON: Vendor-specific extra config
-	Status        string    `json:"status"`                           // "pending", "synced", "failed", "deleted"
-	ErrorMsg      string    `json:"error_msg"`                        // Error message if status is "failed"
-	RetryCount    int       `json:"retry_count" gorm:"default:0"`     // Retry attempt counter
-	CreatedAt     time.Time `json:"created_at" gorm:"index"`
-	UpdatedAt     time.Time `json:"updated_at"`
-	SyncedAt      *time.Time `json:"synced_at"`                        // Last successful sync time
+	ID               int64      `json:"id,string" gorm:"primaryKey"`  // Primary key ID
+	UserID           int64      `json:"user_id,string" gorm:"index"`  // User ID
+	NasID            int64      `json:"nas_id,string" gorm:"index"`   // NAS device ID
+	NasAddr          string     `json:"nas_addr"`                     // NAS IP address
+	VendorCode       string     `json:"vendor_code" gorm:"index"`     // Vendor code (14988=Mikrotik, 2011=Huawei, etc)
+	QoSName          string     `json:"qos_name"`                     // Queue/Profile name in device
+	QoSType          string     `json:"qos_type"`                     // Type: "simple_queue", "tree", "policy", "profile"
+	UpRate           int        `json:"up_rate"`                      // Upload rate in Kbps
+	DownRate         int        `json:"down_rate"`                    // Download rate in Kbps
+	Method           string     `json:"method"`                       // Communication method: "api", "snmp", "cli"
+	RemoteID         string     `json:"remote_id"`                    // Queue/Profile ID in remote device
+	RemoteConfig     string     `json:"remote_config"`                // JSON: Vendor-specific extra config
+	Status           string     `json:"status"`                       // "pending", "synced", "failed", "deleted"
+	ErrorMsg         string     `json:"error_msg"`                    // Error message if status is "failed"
+	DeviceError      string     `json:"device_error"`                 // Full error reported by the device at the last failed sync
+	RetryCount       int        `json:"retry_count" gorm:"default:0"` // Retry attempt counter
+	CreatedAt        time.Time  `json:"created_at" gorm:"index"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	SyncedAt         *time.Time `json:"synced_at"`          // Last successful sync time
+	SyncedUpRate     int        `json:"synced_up_rate"`     // Upload rate pushed at last successful sync
+	SyncedDownRate   int        `json:"synced_down_rate"`   // Download rate pushed at last successful sync
+	SyncedConfigHash string     `json:"synced_config_hash"` // Hash of the full queue config pushed at last successful sync
 }
 
 // TableName specifies the table name
@@ -96,6 +96,7 @@ type RadiusOnline struct {
 	AcctOutputTotal     int64     `json:"acct_output_total,string"`
 	AcctInputPackets    int       `json:"acct_input_packets"`
 	AcctOutputPackets   int       `json:"acct_output_packets"`
+	AcctInterimInterval int       `json:"acct_interim_interval"` // Interim update interval in seconds
 	AcctStartTime       time.Time `gorm:"index" json:"acct_start_time"`
 	LastUpdate          time.Time `json:"last_update"`
 }
@@ -119,6 +120,20 @@ func (r RadiusOnline) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// RadiusOnlineStat is a periodic sample of online sessions and throughput
+type RadiusOnlineStat struct {
+	ID          int64     `json:"id,string"` // Primary key ID
+	SampleTime  time.Time `gorm:"index" json:"sample_time"`
+	OnlineCount int64     `json:"online_count"`
+	InputRate   int64     `json:"input_rate"`  // Upstream throughput in bits per second
+	OutputRate  int64     `json:"output_rate"` // Downstream throughput in bits per second
+}
+
+// TableName Specify table name
+func (RadiusOnlineStat) TableName() string {
+	return "radius_online_stat"
+}
+
 // RadiusAccounting
 // Radius Accounting Recode
 type RadiusAccounting struct {
@@ -126,7 +141,7 @@ type RadiusAccounting struct {
 	Username            string    `gorm:"index" json:"username"`
 	AcctSessionId       string    `gorm:"index" json:"acct_session_id"`
 	NasId               string    `json:"nas_id"`
-	NasAddr             string    `json:"nas_addr"`
+	NasAddr             string    `gorm:"index:idx_radius_accounting_nas_start,priority:1" json:"nas_addr"`
 	NasPaddr            string    `json:"nas_paddr"`
 	SessionTimeout      int       `json:"session_timeout"`
 	FramedIpaddr        string    `json:"framed_ipaddr"`
@@ -146,7 +161,7 @@ type RadiusAccounting struct {
 	AcctInputPackets    int       `json:"acct_input_packets"`
 	AcctOutputPackets   int       `json:"acct_output_packets"`
 	LastUpdate          time.Time `json:"last_update"`
-	AcctStartTime       time.Time `gorm:"index" json:"acct_start_time"`
+	AcctStartTime       time.Time `gorm:"index;index:idx_radius_accounting_nas_start,priority:2" json:"acct_start_time"`
 	AcctStopTime        time.Time `gorm:"index" json:"acct_stop_time"`
 }
 
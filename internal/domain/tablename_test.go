@@ -46,6 +46,11 @@ func TestRadiusOnline_TableName(t *testing.T) {
 	assert.Equal(t, "radius_online", model.TableName())
 }
 
+func TestRadiusOnlineStat_TableName(t *testing.T) {
+	model := RadiusOnlineStat{}
+	assert.Equal(t, "radius_online_stat", model.TableName())
+}
+
 func TestRadiusAccounting_TableName(t *testing.T) {
 	model := RadiusAccounting{}
 	assert.Equal(t, "radius_accounting", model.TableName())
@@ -83,15 +88,18 @@ func TestTableNameUniqueness(t *testing.T) {
 
 	// Ensure all table names follow snake_case
 	expectedNames := map[string]bool{
-		"sys_config":        true,
-		"sys_opr":           true,
-		"sys_opr_log":       true,
-		"net_node":          true,
-		"net_nas":           true,
-		"radius_profile":    true,
-		"radius_user":       true,
-		"radius_online":     true,
-		"radius_accounting": true,
+		"sys_config":         true,
+		"sys_opr":            true,
+		"sys_opr_log":        true,
+		"net_node":           true,
+		"net_nas":            true,
+		"radius_profile":     true,
+		"radius_user":        true,
+		"radius_online":      true,
+		"radius_online_stat": true,
+		"radius_accounting":  true,
+		"nas_qos":            true,
+		"nas_qos_log":        true,
 	}
 
 	assert.Equal(t, len(expectedNames), len(tableNames), "Table name count should match")
@@ -14,6 +14,7 @@ var Tables = []interface{}{
 	// Radius
 	&RadiusAccounting{},
 	&RadiusOnline{},
+	&RadiusOnlineStat{},
 	&RadiusProfile{},
 	&RadiusUser{},
 }
@@ -6,6 +6,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2865"
 )
 
 // mockStage is a simple test implementation of AuthPipelineStage
@@ -415,3 +418,33 @@ func TestAuthPipeline_ConcurrentAccess(t *testing.T) {
 	stages := p.Stages()
 	assert.Greater(t, len(stages), 1)
 }
+
+func TestStageVendorParsing_MacAuthGate(t *testing.T) {
+	s := &AuthService{RadiusService: &RadiusService{}}
+	const mac = "AA:BB:CC:DD:EE:FF"
+
+	tests := []struct {
+		name     string
+		username string
+		disabled bool
+		macAuth  bool
+	}{
+		{name: "username equals calling MAC", username: mac, macAuth: true},
+		{name: "regular username", username: "alice", macAuth: false},
+		{name: "MAC auth disabled on NAS", username: mac, disabled: true, macAuth: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packet := radius.New(radius.CodeAccessRequest, []byte("secret"))
+			require.NoError(t, rfc2865.CallingStationID_SetString(packet, "AA-BB-CC-DD-EE-FF"))
+
+			ctx := NewAuthPipelineContext(s, nil, &radius.Request{Packet: packet})
+			ctx.Username = tt.username
+			ctx.NAS = &domain.NetNas{VendorCode: "0", MacAuthDisabled: tt.disabled}
+
+			require.NoError(t, s.stageVendorParsing(ctx))
+			assert.Equal(t, tt.macAuth, ctx.IsMacAuth)
+		})
+	}
+}
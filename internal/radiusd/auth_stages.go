@@ -107,7 +107,8 @@ func (s *AuthService) stageVendorParsing(ctx *AuthPipelineContext) error {
 	vendorReq := s.ParseVendor(ctx.Request, ctx.NAS.VendorCode)
 	ctx.VendorRequest = vendorReq
 
-	ctx.IsMacAuth = vendorReq.MacAddr != "" && vendorReq.MacAddr == ctx.Username
+	// Devices with MAC authentication disabled are authenticated by password only
+	ctx.IsMacAuth = vendorReq.MacAddr != "" && vendorReq.MacAddr == ctx.Username && !ctx.NAS.MacAuthDisabled
 
 	ctx.VendorRequestForPlugin = &vendorparsers.VendorRequest{
 		MacAddr: vendorReq.MacAddr,
@@ -8,11 +8,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/app"
 	"github.com/talkincode/toughradius/v9/internal/domain"
 	"github.com/talkincode/toughradius/v9/internal/radiusd/plugins/accounting"
 	vendorparserspkg "github.com/talkincode/toughradius/v9/internal/radiusd/plugins/vendorparsers"
 	"layeh.com/radius"
 	"layeh.com/radius/rfc2866"
+	"layeh.com/radius/rfc2869"
 )
 
 // mockSessionRepository is a test mock for SessionRepository
@@ -50,6 +52,9 @@ func (m *mockSessionRepository) Update(ctx context.Context, session *domain.Radi
 		existing.AcctOutputTotal = session.AcctOutputTotal
 		existing.AcctInputPackets = session.AcctInputPackets
 		existing.AcctOutputPackets = session.AcctOutputPackets
+		if session.AcctInterimInterval > 0 {
+			existing.AcctInterimInterval = session.AcctInterimInterval
+		}
 		existing.LastUpdate = session.LastUpdate
 	}
 	return nil
@@ -163,17 +168,17 @@ func TestNewStartHandler(t *testing.T) {
 	sessionRepo := newMockSessionRepo()
 	acctRepo := newMockAccountingRepo()
 
-	handler := NewStartHandler(sessionRepo, acctRepo)
+	handler := NewStartHandler(sessionRepo, acctRepo, nil)
 	assert.NotNil(t, handler)
 }
 
 func TestStartHandler_Name(t *testing.T) {
-	handler := NewStartHandler(nil, nil)
+	handler := NewStartHandler(nil, nil, nil)
 	assert.Equal(t, "StartHandler", handler.Name())
 }
 
 func TestStartHandler_CanHandle(t *testing.T) {
-	handler := NewStartHandler(nil, nil)
+	handler := NewStartHandler(nil, nil, nil)
 
 	tests := []struct {
 		name       string
@@ -197,7 +202,7 @@ func TestStartHandler_CanHandle(t *testing.T) {
 func TestStartHandler_Handle_Success(t *testing.T) {
 	sessionRepo := newMockSessionRepo()
 	acctRepo := newMockAccountingRepo()
-	handler := NewStartHandler(sessionRepo, acctRepo)
+	handler := NewStartHandler(sessionRepo, acctRepo, nil)
 
 	ctx := createMockAccountingContext(int(rfc2866.AcctStatusType_Value_Start))
 	err := handler.Handle(ctx)
@@ -211,7 +216,7 @@ func TestStartHandler_Handle_SessionCreateError(t *testing.T) {
 	sessionRepo := newMockSessionRepo()
 	sessionRepo.createErr = errors.New("database error")
 	acctRepo := newMockAccountingRepo()
-	handler := NewStartHandler(sessionRepo, acctRepo)
+	handler := NewStartHandler(sessionRepo, acctRepo, nil)
 
 	ctx := createMockAccountingContext(int(rfc2866.AcctStatusType_Value_Start))
 	err := handler.Handle(ctx)
@@ -224,7 +229,7 @@ func TestStartHandler_Handle_AccountingCreateError(t *testing.T) {
 	sessionRepo := newMockSessionRepo()
 	acctRepo := newMockAccountingRepo()
 	acctRepo.createErr = errors.New("accounting error")
-	handler := NewStartHandler(sessionRepo, acctRepo)
+	handler := NewStartHandler(sessionRepo, acctRepo, nil)
 
 	ctx := createMockAccountingContext(int(rfc2866.AcctStatusType_Value_Start))
 	err := handler.Handle(ctx)
@@ -237,7 +242,7 @@ func TestStartHandler_Handle_AccountingCreateError(t *testing.T) {
 func TestStartHandler_Handle_NilVendorRequest(t *testing.T) {
 	sessionRepo := newMockSessionRepo()
 	acctRepo := newMockAccountingRepo()
-	handler := NewStartHandler(sessionRepo, acctRepo)
+	handler := NewStartHandler(sessionRepo, acctRepo, nil)
 
 	ctx := createMockAccountingContext(int(rfc2866.AcctStatusType_Value_Start))
 	ctx.VendorReq = nil // Explicitly nil
@@ -330,17 +335,17 @@ func TestStopHandler_Handle_NilVendorRequest(t *testing.T) {
 func TestNewUpdateHandler(t *testing.T) {
 	sessionRepo := newMockSessionRepo()
 	acctRepo := newMockAccountingRepo()
-	handler := NewUpdateHandler(sessionRepo, acctRepo)
+	handler := NewUpdateHandler(sessionRepo, acctRepo, nil)
 	assert.NotNil(t, handler)
 }
 
 func TestUpdateHandler_Name(t *testing.T) {
-	handler := NewUpdateHandler(nil, nil)
+	handler := NewUpdateHandler(nil, nil, nil)
 	assert.Equal(t, "UpdateHandler", handler.Name())
 }
 
 func TestUpdateHandler_CanHandle(t *testing.T) {
-	handler := NewUpdateHandler(nil, nil)
+	handler := NewUpdateHandler(nil, nil, nil)
 
 	tests := []struct {
 		name       string
@@ -367,7 +372,7 @@ func TestUpdateHandler_Handle_Success(t *testing.T) {
 		AcctSessionId: "test-session-123",
 		Username:      "testuser",
 	}
-	handler := NewUpdateHandler(sessionRepo, acctRepo)
+	handler := NewUpdateHandler(sessionRepo, acctRepo, nil)
 
 	ctx := createMockAccountingContext(int(rfc2866.AcctStatusType_Value_InterimUpdate))
 	err := handler.Handle(ctx)
@@ -379,7 +384,8 @@ func TestUpdateHandler_Handle_UpdateError(t *testing.T) {
 	sessionRepo := newMockSessionRepo()
 	acctRepo := newMockAccountingRepo()
 	sessionRepo.updateErr = errors.New("update failed")
-	handler := NewUpdateHandler(sessionRepo, acctRepo)
+	sessionRepo.sessions["test-session-123"] = &domain.RadiusOnline{AcctSessionId: "test-session-123"}
+	handler := NewUpdateHandler(sessionRepo, acctRepo, nil)
 
 	ctx := createMockAccountingContext(int(rfc2866.AcctStatusType_Value_InterimUpdate))
 	err := handler.Handle(ctx)
@@ -390,7 +396,7 @@ func TestUpdateHandler_Handle_UpdateError(t *testing.T) {
 func TestUpdateHandler_Handle_NilVendorRequest(t *testing.T) {
 	sessionRepo := newMockSessionRepo()
 	acctRepo := newMockAccountingRepo()
-	handler := NewUpdateHandler(sessionRepo, acctRepo)
+	handler := NewUpdateHandler(sessionRepo, acctRepo, nil)
 
 	ctx := createMockAccountingContext(int(rfc2866.AcctStatusType_Value_InterimUpdate))
 	ctx.VendorReq = nil
@@ -499,9 +505,9 @@ func TestHandlers_ImplementAccountingHandler(t *testing.T) {
 
 	// Verify all handlers implement AccountingHandler interface
 	handlers := []accounting.AccountingHandler{
-		NewStartHandler(sessionRepo, acctRepo),
+		NewStartHandler(sessionRepo, acctRepo, nil),
 		NewStopHandler(sessionRepo, acctRepo),
-		NewUpdateHandler(sessionRepo, newMockAccountingRepo()),
+		NewUpdateHandler(sessionRepo, newMockAccountingRepo(), nil),
 		NewNasStateHandler(sessionRepo),
 	}
 
@@ -518,7 +524,7 @@ func TestBuildOnlineFromRequest(t *testing.T) {
 	sessionRepo.sessions["test-session-123"] = &domain.RadiusOnline{
 		AcctSessionId: "test-session-123",
 	}
-	handler := NewUpdateHandler(sessionRepo, acctRepo)
+	handler := NewUpdateHandler(sessionRepo, acctRepo, nil)
 
 	ctx := createMockAccountingContext(int(rfc2866.AcctStatusType_Value_InterimUpdate))
 	err := handler.Handle(ctx)
@@ -528,3 +534,73 @@ func TestBuildOnlineFromRequest(t *testing.T) {
 	session := sessionRepo.sessions["test-session-123"]
 	assert.NotNil(t, session)
 }
+
+// stubConfigGetter serves radius settings by name
+type stubConfigGetter map[string]int64
+
+func (s stubConfigGetter) GetInt64(category, name string) int64 {
+	return s[name]
+}
+
+func TestAcctInterimInterval(t *testing.T) {
+	withInterval := func(statusType int, interval uint32) *accounting.AccountingContext {
+		ctx := createMockAccountingContext(statusType)
+		_ = rfc2869.AcctInterimInterval_Set(ctx.Request.Packet, rfc2869.AcctInterimInterval(interval)) //nolint:errcheck
+		return ctx
+	}
+
+	t.Run("Start stores the reported interval", func(t *testing.T) {
+		sessionRepo := newMockSessionRepo()
+		handler := NewStartHandler(sessionRepo, newMockAccountingRepo(), nil)
+
+		require.NoError(t, handler.Handle(withInterval(int(rfc2866.AcctStatusType_Value_Start), 600)))
+		assert.Equal(t, 600, sessionRepo.sessions["test-session-123"].AcctInterimInterval)
+	})
+
+	t.Run("Start defaults a missing interval", func(t *testing.T) {
+		sessionRepo := newMockSessionRepo()
+		handler := NewStartHandler(sessionRepo, newMockAccountingRepo(), nil)
+
+		require.NoError(t, handler.Handle(createMockAccountingContext(int(rfc2866.AcctStatusType_Value_Start))))
+		assert.Equal(t, app.DefaultAcctInterimInterval, sessionRepo.sessions["test-session-123"].AcctInterimInterval)
+	})
+
+	t.Run("Start falls back to the configured interval", func(t *testing.T) {
+		sessionRepo := newMockSessionRepo()
+		handler := NewStartHandler(sessionRepo, newMockAccountingRepo(), stubConfigGetter{app.ConfigRadiusAcctInterimInterval: 120})
+
+		require.NoError(t, handler.Handle(createMockAccountingContext(int(rfc2866.AcctStatusType_Value_Start))))
+		assert.Equal(t, 120, sessionRepo.sessions["test-session-123"].AcctInterimInterval)
+	})
+
+	t.Run("Update without a start defaults a missing interval", func(t *testing.T) {
+		sessionRepo := newMockSessionRepo()
+		handler := NewUpdateHandler(sessionRepo, newMockAccountingRepo(), nil)
+
+		require.NoError(t, handler.Handle(createMockAccountingContext(int(rfc2866.AcctStatusType_Value_InterimUpdate))))
+		assert.Equal(t, app.DefaultAcctInterimInterval, sessionRepo.sessions["test-session-123"].AcctInterimInterval)
+	})
+
+	t.Run("Update without a start falls back to the configured interval", func(t *testing.T) {
+		sessionRepo := newMockSessionRepo()
+		handler := NewUpdateHandler(sessionRepo, newMockAccountingRepo(), stubConfigGetter{app.ConfigRadiusAcctInterimInterval: 120})
+
+		require.NoError(t, handler.Handle(createMockAccountingContext(int(rfc2866.AcctStatusType_Value_InterimUpdate))))
+		assert.Equal(t, 120, sessionRepo.sessions["test-session-123"].AcctInterimInterval)
+	})
+
+	t.Run("Update replaces the interval only when reported", func(t *testing.T) {
+		sessionRepo := newMockSessionRepo()
+		sessionRepo.sessions["test-session-123"] = &domain.RadiusOnline{
+			AcctSessionId:       "test-session-123",
+			AcctInterimInterval: 300,
+		}
+		handler := NewUpdateHandler(sessionRepo, newMockAccountingRepo(), nil)
+
+		require.NoError(t, handler.Handle(createMockAccountingContext(int(rfc2866.AcctStatusType_Value_InterimUpdate))))
+		assert.Equal(t, 300, sessionRepo.sessions["test-session-123"].AcctInterimInterval)
+
+		require.NoError(t, handler.Handle(withInterval(int(rfc2866.AcctStatusType_Value_InterimUpdate), 120)))
+		assert.Equal(t, 120, sessionRepo.sessions["test-session-123"].AcctInterimInterval)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/talkincode/toughradius/v9/internal/app"
 	"github.com/talkincode/toughradius/v9/internal/domain"
 	"github.com/talkincode/toughradius/v9/internal/radiusd/plugins/accounting"
 	vendorparserspkg "github.com/talkincode/toughradius/v9/internal/radiusd/plugins/vendorparsers"
@@ -20,16 +21,23 @@ import (
 type StartHandler struct {
 	sessionRepo    repository.SessionRepository
 	accountingRepo repository.AccountingRepository
+	configGetter   configInt64Getter
+}
+
+type configInt64Getter interface {
+	GetInt64(category, name string) int64
 }
 
 // NewStartHandler CreateAccounting Start handler
 func NewStartHandler(
 	sessionRepo repository.SessionRepository,
 	accountingRepo repository.AccountingRepository,
+	getter configInt64Getter,
 ) *StartHandler {
 	return &StartHandler{
 		sessionRepo:    sessionRepo,
 		accountingRepo: accountingRepo,
+		configGetter:   getter,
 	}
 }
 
@@ -110,11 +118,33 @@ func (h *StartHandler) buildRadiusOnline(r *radius.Request, vr *vendorparserspkg
 		AcctOutputTotal:     int64(acctOutputOctets) + int64(acctOutputGigawords)*4*1024*1024*1024,
 		AcctInputPackets:    int(rfc2866.AcctInputPackets_Get(r.Packet)),
 		AcctOutputPackets:   int(rfc2866.AcctOutputPackets_Get(r.Packet)),
+		AcctInterimInterval: interimIntervalOrDefault(r, h.configGetter),
 		AcctStartTime:       getAcctStartTime(int(rfc2866.AcctSessionTime_Get(r.Packet))),
 		LastUpdate:          time.Now(),
 	}
 }
 
+// acctInterimInterval returns the Acct-Interim-Interval reported by the NAS in
+// seconds, or 0 when the attribute is absent
+func acctInterimInterval(r *radius.Request) int {
+	return int(rfc2869.AcctInterimInterval_Get(r.Packet))
+}
+
+// interimIntervalOrDefault returns the reported interim interval, falling back
+// to the configured radius.AcctInterimInterval, the value sent to the NAS in
+// Access-Accept
+func interimIntervalOrDefault(r *radius.Request, getter configInt64Getter) int {
+	if interval := acctInterimInterval(r); interval > 0 {
+		return interval
+	}
+	if getter != nil {
+		if interval := getter.GetInt64("radius", app.ConfigRadiusAcctInterimInterval); interval > 0 {
+			return int(interval)
+		}
+	}
+	return app.DefaultAcctInterimInterval
+}
+
 func (h *StartHandler) buildRadiusAccounting(online *domain.RadiusOnline, start bool) domain.RadiusAccounting {
 	accounting := domain.RadiusAccounting{
 		ID:                  common.UUIDint64(),
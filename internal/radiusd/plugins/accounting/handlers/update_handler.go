@@ -18,13 +18,15 @@ import (
 type UpdateHandler struct {
 	sessionRepo    repository.SessionRepository
 	accountingRepo repository.AccountingRepository
+	configGetter   configInt64Getter
 }
 
 // NewUpdateHandler CreateAccounting Update handler
-func NewUpdateHandler(sessionRepo repository.SessionRepository, accountingRepo repository.AccountingRepository) *UpdateHandler {
+func NewUpdateHandler(sessionRepo repository.SessionRepository, accountingRepo repository.AccountingRepository, getter configInt64Getter) *UpdateHandler {
 	return &UpdateHandler{
 		sessionRepo:    sessionRepo,
 		accountingRepo: accountingRepo,
+		configGetter:   getter,
 	}
 }
 
@@ -60,28 +62,29 @@ func (h *UpdateHandler) Handle(acctCtx *accounting.AccountingContext) error {
 	if !exists {
 		// Build a complete session record from interim-update packet
 		fullOnline := domain.RadiusOnline{
-			ID:                common.UUIDint64(),
-			Username:          acctCtx.Username,
-			NasId:             acctCtx.NAS.Identifier,
-			NasAddr:           acctCtx.NAS.Ipaddr,
-			NasPaddr:          acctCtx.NASIP,
-			SessionTimeout:    int(rfc2865.SessionTimeout_Get(acctCtx.Request.Packet)),
-			FramedIpaddr:      common.IfEmptyStr(rfc2865.FramedIPAddress_Get(acctCtx.Request.Packet).String(), common.NA),
-			FramedNetmask:     common.IfEmptyStr(rfc2865.FramedIPNetmask_Get(acctCtx.Request.Packet).String(), common.NA),
-			MacAddr:           vendorReq.MacAddr,
-			NasPort:           0, // Not available in accounting requests typically
-			NasClass:          common.NA,
-			NasPortId:         common.IfEmptyStr(rfc2869.NASPortID_GetString(acctCtx.Request.Packet), common.NA),
-			NasPortType:       0, // Not available in accounting requests typically
-			ServiceType:       0, // Not available in accounting requests typically
-			AcctSessionId:     online.AcctSessionId,
-			AcctSessionTime:   online.AcctSessionTime,
-			AcctInputTotal:    online.AcctInputTotal,
-			AcctOutputTotal:   online.AcctOutputTotal,
-			AcctInputPackets:  online.AcctInputPackets,
-			AcctOutputPackets: online.AcctOutputPackets,
-			AcctStartTime:     time.Now().Add(-time.Duration(online.AcctSessionTime) * time.Second),
-			LastUpdate:        time.Now(),
+			ID:                  common.UUIDint64(),
+			Username:            acctCtx.Username,
+			NasId:               acctCtx.NAS.Identifier,
+			NasAddr:             acctCtx.NAS.Ipaddr,
+			NasPaddr:            acctCtx.NASIP,
+			SessionTimeout:      int(rfc2865.SessionTimeout_Get(acctCtx.Request.Packet)),
+			FramedIpaddr:        common.IfEmptyStr(rfc2865.FramedIPAddress_Get(acctCtx.Request.Packet).String(), common.NA),
+			FramedNetmask:       common.IfEmptyStr(rfc2865.FramedIPNetmask_Get(acctCtx.Request.Packet).String(), common.NA),
+			MacAddr:             vendorReq.MacAddr,
+			NasPort:             0, // Not available in accounting requests typically
+			NasClass:            common.NA,
+			NasPortId:           common.IfEmptyStr(rfc2869.NASPortID_GetString(acctCtx.Request.Packet), common.NA),
+			NasPortType:         0, // Not available in accounting requests typically
+			ServiceType:         0, // Not available in accounting requests typically
+			AcctSessionId:       online.AcctSessionId,
+			AcctSessionTime:     online.AcctSessionTime,
+			AcctInputTotal:      online.AcctInputTotal,
+			AcctOutputTotal:     online.AcctOutputTotal,
+			AcctInputPackets:    online.AcctInputPackets,
+			AcctOutputPackets:   online.AcctOutputPackets,
+			AcctInterimInterval: interimIntervalOrDefault(acctCtx.Request, h.configGetter),
+			AcctStartTime:       time.Now().Add(-time.Duration(online.AcctSessionTime) * time.Second),
+			LastUpdate:          time.Now(),
 		}
 
 		err := h.sessionRepo.Create(acctCtx.Context, &fullOnline)
@@ -158,6 +161,8 @@ func buildOnlineFromRequest(acctCtx *accounting.AccountingContext, vr *vendorpar
 		AcctOutputTotal:   int64(acctOutputOctets) + int64(acctOutputGigawords)*4*1024*1024*1024,
 		AcctInputPackets:  int(rfc2866.AcctInputPackets_Get(r.Packet)),
 		AcctOutputPackets: int(rfc2866.AcctOutputPackets_Get(r.Packet)),
-		LastUpdate:        time.Now(),
+		// Only a reported interval replaces the stored one
+		AcctInterimInterval: acctInterimInterval(r),
+		LastUpdate:          time.Now(),
 	}
 }
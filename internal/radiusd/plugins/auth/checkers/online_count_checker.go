@@ -2,20 +2,59 @@ package checkers
 
 import (
 	"context"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/talkincode/toughradius/v9/internal/radiusd/errors"
 	"github.com/talkincode/toughradius/v9/internal/radiusd/plugins/auth"
 	"github.com/talkincode/toughradius/v9/internal/radiusd/repository"
+	"layeh.com/radius/rfc2865"
 )
 
+const (
+	// onlineCountLockStripes is the number of per-username lock stripes
+	onlineCountLockStripes = 64
+	// defaultReservationTTL is how long an accepted login holds a slot while
+	// its Accounting-Start has not yet created the online session
+	defaultReservationTTL = 30 * time.Second
+)
+
+// loginReservation tracks logins accepted for a user that may not be visible
+// as online sessions yet
+type loginReservation struct {
+	base   int                  // Online count observed when the first pending login was accepted
+	logins map[string]time.Time // Pending login key to when it stops holding a slot
+}
+
 // OnlineCountChecker enforces online count limits
 type OnlineCountChecker struct {
 	sessionRepo repository.SessionRepository
+
+	// The online session is only created at Accounting-Start, long after the
+	// Access-Accept, so the count and the insert cannot share a transaction.
+	// Logins for the same username are serialized instead and every accepted
+	// login reserves a slot until its session shows up or the reservation expires.
+	locks          [onlineCountLockStripes]sync.Mutex
+	mu             sync.Mutex
+	reservations   map[string]*loginReservation
+	reservationTTL time.Duration
+	lastSweep      time.Time
+	now            func() time.Time
+	anonymous      atomic.Int64 // Numbers logins without a request to key them by
 }
 
 // NewOnlineCountChecker creates an online count checker
 func NewOnlineCountChecker(sessionRepo repository.SessionRepository) *OnlineCountChecker {
-	return &OnlineCountChecker{sessionRepo: sessionRepo}
+	return &OnlineCountChecker{
+		sessionRepo:    sessionRepo,
+		reservations:   make(map[string]*loginReservation),
+		reservationTTL: defaultReservationTTL,
+		now:            time.Now,
+	}
 }
 
 func (c *OnlineCountChecker) Name() string {
@@ -41,14 +80,108 @@ func (c *OnlineCountChecker) Check(ctx context.Context, authCtx *auth.AuthContex
 		return nil
 	}
 
+	lock := c.userLock(user.Username)
+	lock.Lock()
+	defer lock.Unlock()
+
 	count, err := c.sessionRepo.CountByUsername(ctx, user.Username)
 	if err != nil {
 		return err
 	}
 
-	if count >= activeNum {
+	key := c.loginKey(authCtx)
+	if c.effectiveCount(user.Username, key, count) >= activeNum {
 		return errors.NewOnlineLimitError("user online count exceeded")
 	}
 
+	c.reserve(user.Username, key, count)
 	return nil
 }
+
+// loginKey identifies the device behind a login so that a retransmitted
+// Access-Request, or a reconnect of the same device, reuses its pending
+// slot instead of taking another: the NAS and Calling-Station-Id when sent,
+// otherwise the NAS and the request's identifier and authenticator
+func (c *OnlineCountChecker) loginKey(authCtx *auth.AuthContext) string {
+	if authCtx.Request == nil || authCtx.Request.Packet == nil {
+		return fmt.Sprintf("#%d", c.anonymous.Add(1))
+	}
+	packet := authCtx.Request.Packet
+
+	nas := ""
+	if authCtx.Nas != nil {
+		nas = authCtx.Nas.Ipaddr
+	} else if authCtx.Request.RemoteAddr != nil {
+		nas = authCtx.Request.RemoteAddr.String()
+	}
+	if station := rfc2865.CallingStationID_GetString(packet); station != "" {
+		return nas + "|station|" + station
+	}
+	return fmt.Sprintf("%s|request|%d|%s", nas, packet.Identifier, hex.EncodeToString(packet.Authenticator[:]))
+}
+
+// userLock returns the lock stripe serializing logins of a username
+func (c *OnlineCountChecker) userLock(username string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(username)) //nolint:errcheck
+	return &c.locks[h.Sum32()%onlineCountLockStripes]
+}
+
+// effectiveCount combines the stored online count with logins that were
+// accepted but whose sessions may not have been created yet. The pending
+// login of key itself is not counted, as this login replaces it.
+func (c *OnlineCountChecker) effectiveCount(username, key string, count int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r, ok := c.reservations[username]
+	if !ok {
+		return count
+	}
+	now := c.now()
+	others := 0
+	for login, expires := range r.logins {
+		switch {
+		case !now.Before(expires):
+			delete(r.logins, login)
+		case login != key:
+			others++
+		}
+	}
+	if len(r.logins) == 0 {
+		delete(c.reservations, username)
+		return count
+	}
+	if pending := r.base + others; pending > count {
+		return pending
+	}
+	return count
+}
+
+// reserve records an accepted login for username
+func (c *OnlineCountChecker) reserve(username, key string, count int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if now.Sub(c.lastSweep) >= c.reservationTTL {
+		for name, r := range c.reservations {
+			for login, expires := range r.logins {
+				if !now.Before(expires) {
+					delete(r.logins, login)
+				}
+			}
+			if len(r.logins) == 0 {
+				delete(c.reservations, name)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	r, ok := c.reservations[username]
+	if !ok {
+		r = &loginReservation{base: count, logins: make(map[string]time.Time)}
+		c.reservations[username] = r
+	}
+	r.logins[key] = now.Add(c.reservationTTL)
+}
@@ -3,13 +3,18 @@ package checkers
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/talkincode/toughradius/v9/internal/domain"
 	radiusErrors "github.com/talkincode/toughradius/v9/internal/radiusd/errors"
 	"github.com/talkincode/toughradius/v9/internal/radiusd/plugins/auth"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2865"
 )
 
 // mockSessionRepository simulates a SessionRepository
@@ -149,3 +154,127 @@ func TestOnlineCountChecker_Check(t *testing.T) {
 		})
 	}
 }
+
+func TestOnlineCountChecker_ConcurrentLogins(t *testing.T) {
+	ctx := context.Background()
+
+	// Sessions are only created at Accounting-Start, so the stored count
+	// stays at zero while the logins race
+	checker := NewOnlineCountChecker(&mockSessionRepository{})
+
+	const activeNum = 3
+	const logins = 50
+
+	var accepted int64
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < logins; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			authCtx := &auth.AuthContext{
+				User: &domain.RadiusUser{Username: "racer", ActiveNum: activeNum},
+			}
+			if err := checker.Check(ctx, authCtx); err == nil {
+				atomic.AddInt64(&accepted, 1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, int64(activeNum), atomic.LoadInt64(&accepted))
+
+	// Other users are not affected by the reservations
+	other := &auth.AuthContext{User: &domain.RadiusUser{Username: "other", ActiveNum: 1}}
+	require.NoError(t, checker.Check(ctx, other))
+}
+
+func TestOnlineCountChecker_ReservationLifecycle(t *testing.T) {
+	ctx := context.Background()
+
+	online := 0
+	checker := NewOnlineCountChecker(&mockSessionRepository{
+		countByUsername: func(ctx context.Context, username string) (int, error) {
+			return online, nil
+		},
+	})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	checker.now = func() time.Time { return now }
+
+	login := func() error {
+		return checker.Check(ctx, &auth.AuthContext{
+			User: &domain.RadiusUser{Username: "alice", ActiveNum: 2},
+		})
+	}
+
+	require.NoError(t, login())
+
+	// The first session started; its reservation must not count twice
+	online = 1
+	require.NoError(t, login())
+	assert.Error(t, login())
+
+	// Logins that never started a session release their slots after the TTL
+	online = 0
+	now = now.Add(defaultReservationTTL)
+	require.NoError(t, login())
+	assert.Len(t, checker.reservations, 1)
+}
+
+func TestOnlineCountChecker_ReconnectReusesSlot(t *testing.T) {
+	ctx := context.Background()
+
+	checker := NewOnlineCountChecker(&mockSessionRepository{})
+	nas := &domain.NetNas{Ipaddr: "10.0.0.1"}
+
+	login := func(identifier byte, station string) error {
+		packet := radius.New(radius.CodeAccessRequest, []byte("secret"))
+		packet.Identifier = identifier
+		require.NoError(t, rfc2865.CallingStationID_SetString(packet, station))
+		return checker.Check(ctx, &auth.AuthContext{
+			User:    &domain.RadiusUser{Username: "bob", ActiveNum: 1},
+			Request: &radius.Request{Packet: packet},
+			Nas:     nas,
+		})
+	}
+
+	require.NoError(t, login(1, "00:11:22:33:44:55"))
+
+	// The PPPoE session started and dropped again within the reservation TTL,
+	// so the stored count is back to zero when the device reconnects
+	require.NoError(t, login(2, "00:11:22:33:44:55"))
+
+	// Another device is still held back by the pending reconnect
+	assert.Error(t, login(3, "66:77:88:99:aa:bb"))
+}
+
+func TestOnlineCountChecker_RetransmitReusesSlot(t *testing.T) {
+	ctx := context.Background()
+
+	checker := NewOnlineCountChecker(&mockSessionRepository{})
+	nas := &domain.NetNas{Ipaddr: "10.0.0.1"}
+
+	request := func(identifier byte) *radius.Request {
+		packet := radius.New(radius.CodeAccessRequest, []byte("secret"))
+		packet.Identifier = identifier
+		return &radius.Request{Packet: packet}
+	}
+	login := func(req *radius.Request) error {
+		return checker.Check(ctx, &auth.AuthContext{
+			User:    &domain.RadiusUser{Username: "carol", ActiveNum: 1},
+			Request: req,
+			Nas:     nas,
+		})
+	}
+
+	first := request(7)
+	require.NoError(t, login(first))
+
+	// The NAS did not see the Access-Accept and resends the same request
+	require.NoError(t, login(first))
+
+	// A different request for the same user is a second login
+	assert.Error(t, login(request(8)))
+}
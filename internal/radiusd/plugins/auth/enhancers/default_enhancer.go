@@ -48,7 +48,7 @@ func (e *DefaultAcceptEnhancer) Enhance(ctx context.Context, authCtx *auth.AuthC
 		timeout = 0
 	}
 
-	interim := getIntConfig(authCtx, app.ConfigRadiusAcctInterimInterval, 120)
+	interim := getIntConfig(authCtx, app.ConfigRadiusAcctInterimInterval, app.DefaultAcctInterimInterval)
 
 	_ = rfc2865.SessionTimeout_Set(response, rfc2865.SessionTimeout(timeout))           //nolint:errcheck,gosec // G115: timeout is validated
 	_ = rfc2869.AcctInterimInterval_Set(response, rfc2869.AcctInterimInterval(interim)) //nolint:errcheck,gosec // G115: interim is validated
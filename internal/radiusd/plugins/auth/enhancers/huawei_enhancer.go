@@ -2,7 +2,6 @@ package enhancers
 
 import (
 	"context"
-	"math"
 	"net"
 	"strings"
 
@@ -39,19 +38,7 @@ func (e *HuaweiAcceptEnhancer) Enhance(ctx context.Context, authCtx *auth.AuthCo
 		profileCache = authCtx.Metadata["profile_cache"]
 	}
 
-	// Use getter methods for bandwidth rates
-	upRate := user.GetUpRate(profileCache)
-	downRate := user.GetDownRate(profileCache)
-
-	up := clampInt64(int64(upRate)*1024, math.MaxInt32)
-	down := clampInt64(int64(downRate)*1024, math.MaxInt32)
-	upPeak := clampInt64(up*4, math.MaxInt32)
-	downPeak := clampInt64(down*4, math.MaxInt32)
-
-	_ = huawei.HuaweiInputAverageRate_Set(resp, huawei.HuaweiInputAverageRate(up))     //nolint:errcheck,gosec // G115: clamped to MaxInt32
-	_ = huawei.HuaweiInputPeakRate_Set(resp, huawei.HuaweiInputPeakRate(upPeak))       //nolint:errcheck,gosec // G115: clamped to MaxInt32
-	_ = huawei.HuaweiOutputAverageRate_Set(resp, huawei.HuaweiOutputAverageRate(down)) //nolint:errcheck,gosec // G115: clamped to MaxInt32
-	_ = huawei.HuaweiOutputPeakRate_Set(resp, huawei.HuaweiOutputPeakRate(downPeak))   //nolint:errcheck,gosec // G115: clamped to MaxInt32
+	addRateLimit(authCtx, profileCache)
 
 	// Set Huawei FramedIPv6Address if user has a fixed IPv6 address
 	if common.IsNotEmptyAndNA(user.IpV6Addr) {
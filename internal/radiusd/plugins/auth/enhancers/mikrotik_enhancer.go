@@ -2,11 +2,9 @@ package enhancers
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/talkincode/toughradius/v9/internal/radiusd/plugins/auth"
 	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors"
-	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors/mikrotik"
 )
 
 type MikrotikAcceptEnhancer struct{}
@@ -27,19 +25,12 @@ func (e *MikrotikAcceptEnhancer) Enhance(ctx context.Context, authCtx *auth.Auth
 		return nil
 	}
 
-	user := authCtx.User
-	resp := authCtx.Response
-
 	// Get profile cache from metadata
 	var profileCache interface{}
 	if authCtx.Metadata != nil {
 		profileCache = authCtx.Metadata["profile_cache"]
 	}
 
-	// Use getter methods for bandwidth rates
-	upRate := user.GetUpRate(profileCache)
-	downRate := user.GetDownRate(profileCache)
-
-	_ = mikrotik.MikrotikRateLimit_SetString(resp, fmt.Sprintf("%dk/%dk", upRate, downRate)) //nolint:errcheck
+	addRateLimit(authCtx, profileCache)
 	return nil
 }
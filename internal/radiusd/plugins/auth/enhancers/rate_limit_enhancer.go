@@ -0,0 +1,39 @@
+package enhancers
+
+import (
+	"context"
+
+	"github.com/talkincode/toughradius/v9/internal/radiusd/plugins/auth"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors"
+)
+
+// RateLimitAcceptEnhancer adds rate limit attributes for NAS vendors that
+// have no dedicated enhancer: Cisco AV-pairs or the WISPr attributes of pfSense
+type RateLimitAcceptEnhancer struct{}
+
+func NewRateLimitAcceptEnhancer() *RateLimitAcceptEnhancer {
+	return &RateLimitAcceptEnhancer{}
+}
+
+func (e *RateLimitAcceptEnhancer) Name() string {
+	return "accept-rate-limit"
+}
+
+func (e *RateLimitAcceptEnhancer) Enhance(ctx context.Context, authCtx *auth.AuthContext) error {
+	if authCtx == nil || authCtx.Response == nil || authCtx.User == nil || authCtx.Nas == nil {
+		return nil
+	}
+	// Vendors with their own enhancer set their rate attributes there
+	if vendors.SupportsRateLimit(authCtx.Nas.VendorCode) {
+		return nil
+	}
+
+	// Get profile cache from metadata
+	var profileCache interface{}
+	if authCtx.Metadata != nil {
+		profileCache = authCtx.Metadata["profile_cache"]
+	}
+
+	addRateLimit(authCtx, profileCache)
+	return nil
+}
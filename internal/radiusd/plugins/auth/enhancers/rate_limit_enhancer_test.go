@@ -0,0 +1,65 @@
+package enhancers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/plugins/auth"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors/cisco"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2865"
+)
+
+func TestRateLimitAcceptEnhancer_Name(t *testing.T) {
+	assert.Equal(t, "accept-rate-limit", NewRateLimitAcceptEnhancer().Name())
+}
+
+func TestRateLimitAcceptEnhancer_Enhance(t *testing.T) {
+	enhancer := NewRateLimitAcceptEnhancer()
+
+	tests := []struct {
+		name       string
+		nas        *domain.NetNas
+		vendorAttr bool
+	}{
+		{name: "nil NAS", nas: nil},
+		{name: "vendor with dedicated enhancer", nas: &domain.NetNas{VendorCode: vendors.CodeMikrotik}},
+		{name: "Cisco", nas: &domain.NetNas{VendorCode: vendors.CodeCisco}, vendorAttr: true},
+		{name: "pfSense", nas: &domain.NetNas{VendorCode: vendors.CodePfSense}, vendorAttr: true},
+		{name: "generic vendor", nas: &domain.NetNas{VendorCode: vendors.CodeStandard}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := radius.New(radius.CodeAccessAccept, []byte("secret"))
+			authCtx := &auth.AuthContext{
+				Response: response,
+				User:     &domain.RadiusUser{Username: "testuser", UpRate: 1024, DownRate: 2048},
+				Nas:      tt.nas,
+			}
+
+			require.NoError(t, enhancer.Enhance(context.Background(), authCtx))
+
+			_, found := response.Lookup(rfc2865.VendorSpecific_Type)
+			assert.Equal(t, tt.vendorAttr, found)
+		})
+	}
+
+	t.Run("Cisco AV-pairs", func(t *testing.T) {
+		response := radius.New(radius.CodeAccessAccept, []byte("secret"))
+		authCtx := &auth.AuthContext{
+			Response: response,
+			User:     &domain.RadiusUser{Username: "testuser", UpRate: 1024, DownRate: 2048},
+			Nas:      &domain.NetNas{VendorCode: vendors.CodeCisco},
+		}
+		require.NoError(t, enhancer.Enhance(context.Background(), authCtx))
+
+		pairs, err := cisco.CiscoAVPair_GetStrings(response)
+		require.NoError(t, err)
+		assert.Len(t, pairs, 2)
+	})
+}
@@ -2,6 +2,7 @@ package enhancers
 
 import (
 	"github.com/talkincode/toughradius/v9/internal/radiusd/plugins/auth"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors"
 )
 
 func matchVendor(ctx *auth.AuthContext, vendorCode string) bool {
@@ -11,9 +12,12 @@ func matchVendor(ctx *auth.AuthContext, vendorCode string) bool {
 	return ctx.Nas.VendorCode == vendorCode
 }
 
-func clampInt64(val int64, max int64) int64 {
-	if val > max {
-		return max
+// addRateLimit adds the rate limit attributes of the NAS vendor for the
+// user's upload and download rates to the response
+func addRateLimit(authCtx *auth.AuthContext, profileCache interface{}) {
+	user := authCtx.User
+	attrs := vendors.AcceptRateLimitAttributes(authCtx.Nas.VendorCode, user.GetUpRate(profileCache), user.GetDownRate(profileCache))
+	for _, avp := range attrs {
+		authCtx.Response.Add(avp.Type, avp.Attribute)
 	}
-	return val
 }
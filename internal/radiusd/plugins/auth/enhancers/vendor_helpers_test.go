@@ -1,7 +1,6 @@
 package enhancers
 
 import (
-	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -110,62 +109,3 @@ func TestMatchVendor(t *testing.T) {
 		})
 	}
 }
-
-func TestClampInt64(t *testing.T) {
-	tests := []struct {
-		name     string
-		val      int64
-		max      int64
-		expected int64
-	}{
-		{
-			name:     "below max",
-			val:      100,
-			max:      1000,
-			expected: 100,
-		},
-		{
-			name:     "at max",
-			val:      1000,
-			max:      1000,
-			expected: 1000,
-		},
-		{
-			name:     "above max",
-			val:      2000,
-			max:      1000,
-			expected: 1000,
-		},
-		{
-			name:     "zero value",
-			val:      0,
-			max:      1000,
-			expected: 0,
-		},
-		{
-			name:     "negative value",
-			val:      -100,
-			max:      1000,
-			expected: -100,
-		},
-		{
-			name:     "max int32",
-			val:      math.MaxInt32 + 1000,
-			max:      math.MaxInt32,
-			expected: math.MaxInt32,
-		},
-		{
-			name:     "exactly max int32",
-			val:      math.MaxInt32,
-			max:      math.MaxInt32,
-			expected: math.MaxInt32,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := clampInt64(tt.val, tt.max)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
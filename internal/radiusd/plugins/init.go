@@ -33,6 +33,11 @@ func InitPlugins(appCtx app.ConfigManagerProvider, sessionRepo repository.Sessio
 		registry.RegisterPolicyChecker(checkers.NewOnlineCountChecker(sessionRepo))
 	}
 
+	var cfgGetter interface{ GetInt64(string, string) int64 }
+	if appCtx != nil {
+		cfgGetter = appCtx.ConfigMgr()
+	}
+
 	// Register response enhancers
 	registry.RegisterResponseEnhancer(enhancers.NewDefaultAcceptEnhancer())
 	registry.RegisterResponseEnhancer(enhancers.NewHuaweiAcceptEnhancer())
@@ -40,18 +45,15 @@ func InitPlugins(appCtx app.ConfigManagerProvider, sessionRepo repository.Sessio
 	registry.RegisterResponseEnhancer(enhancers.NewZTEAcceptEnhancer())
 	registry.RegisterResponseEnhancer(enhancers.NewMikrotikAcceptEnhancer())
 	registry.RegisterResponseEnhancer(enhancers.NewIkuaiAcceptEnhancer())
+	registry.RegisterResponseEnhancer(enhancers.NewRateLimitAcceptEnhancer())
 
 	// Register authentication guards
-	var cfgGetter interface{ GetInt64(string, string) int64 }
-	if appCtx != nil {
-		cfgGetter = appCtx.ConfigMgr()
-	}
 	registry.RegisterAuthGuard(guards.NewRejectDelayGuard(cfgGetter))
 
 	// Register accounting handlers (dependency injection required)
 	if sessionRepo != nil && accountingRepo != nil {
-		registry.RegisterAccountingHandler(handlers.NewStartHandler(sessionRepo, accountingRepo))
-		registry.RegisterAccountingHandler(handlers.NewUpdateHandler(sessionRepo, accountingRepo))
+		registry.RegisterAccountingHandler(handlers.NewStartHandler(sessionRepo, accountingRepo, cfgGetter))
+		registry.RegisterAccountingHandler(handlers.NewUpdateHandler(sessionRepo, accountingRepo, cfgGetter))
 		registry.RegisterAccountingHandler(handlers.NewStopHandler(sessionRepo, accountingRepo))
 		registry.RegisterAccountingHandler(handlers.NewNasStateHandler(sessionRepo))
 	}
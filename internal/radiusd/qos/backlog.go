@@ -0,0 +1,132 @@
+package qos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	// MetricsQoSBacklog is the gauge holding the pending and failed QoS records across all NAS devices
+	MetricsQoSBacklog = "qos_backlog"
+	// MetricsQoSBacklogNasPrefix prefixes the per-NAS backlog gauges (qos_backlog_nas_<id>)
+	MetricsQoSBacklogNasPrefix = "qos_backlog_nas_"
+)
+
+// BacklogAlertConfig returns the backlog size that triggers an alert and how
+// long it must be exceeded. A threshold of 0 disables the alert.
+type BacklogAlertConfig func() (threshold int64, sustain time.Duration)
+
+// BacklogAlert is raised once when a NAS backlog stays above the threshold
+type BacklogAlert struct {
+	NasID     int64
+	Backlog   int64
+	Threshold int64
+	Since     time.Time
+}
+
+// backlogState tracks when a NAS backlog first exceeded the threshold
+type backlogState struct {
+	since   time.Time
+	alerted bool
+}
+
+// SetBacklogAlertConfig sets where the backlog alert threshold is read from
+func (s *NasQoSService) SetBacklogAlertConfig(config BacklogAlertConfig) {
+	s.backlogMu.Lock()
+	defer s.backlogMu.Unlock()
+	s.backlogConfig = config
+}
+
+// SetBacklogAlertHandler replaces how backlog alerts are delivered, mainly for tests
+func (s *NasQoSService) SetBacklogAlertHandler(handler func(alert BacklogAlert)) {
+	s.backlogMu.Lock()
+	defer s.backlogMu.Unlock()
+	s.backlogAlert = handler
+}
+
+// checkBacklog publishes the pending/failed QoS backlog of every NAS as
+// gauges and raises an alert when a backlog stays above the configured
+// threshold for the sustained period
+func (s *NasQoSService) checkBacklog(ctx context.Context) {
+	var rows []struct {
+		NasID int64
+		Total int64
+	}
+	err := s.db.WithContext(ctx).
+		Model(&domain.NasQoS{}).
+		Select("nas_id, COUNT(*) AS total").
+		Where("status IN ?", []string{"pending", "failed"}).
+		Group("nas_id").
+		Scan(&rows).Error
+	if err != nil {
+		zap.L().Error("failed to count QoS backlog", zap.Error(err))
+		return
+	}
+
+	s.backlogMu.Lock()
+	defer s.backlogMu.Unlock()
+
+	var threshold int64
+	var sustain time.Duration
+	if s.backlogConfig != nil {
+		threshold, sustain = s.backlogConfig()
+	}
+	now := s.now()
+
+	var total int64
+	current := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		current[row.NasID] = row.Total
+		total += row.Total
+		metrics.SetGauge(fmt.Sprintf("%s%d", MetricsQoSBacklogNasPrefix, row.NasID), row.Total)
+	}
+	metrics.SetGauge(MetricsQoSBacklog, total)
+
+	// Zero the gauges of devices whose backlog has drained
+	for nasID := range s.backlogNas {
+		if _, ok := current[nasID]; !ok {
+			metrics.SetGauge(fmt.Sprintf("%s%d", MetricsQoSBacklogNasPrefix, nasID), 0)
+		}
+	}
+	s.backlogNas = current
+
+	for nasID, count := range current {
+		if threshold <= 0 || count <= threshold {
+			delete(s.backlogState, nasID)
+			continue
+		}
+
+		state, ok := s.backlogState[nasID]
+		if !ok {
+			s.backlogState[nasID] = &backlogState{since: now}
+			continue
+		}
+		if state.alerted || now.Sub(state.since) < sustain {
+			continue
+		}
+
+		state.alerted = true
+		s.backlogAlert(BacklogAlert{NasID: nasID, Backlog: count, Threshold: threshold, Since: state.since})
+	}
+
+	for nasID := range s.backlogState {
+		if _, ok := current[nasID]; !ok {
+			delete(s.backlogState, nasID)
+		}
+	}
+}
+
+// logBacklogAlert is the default backlog alert handler
+func logBacklogAlert(alert BacklogAlert) {
+	zap.L().Warn("QoS backlog exceeded threshold",
+		zap.Int64("nas_id", alert.NasID),
+		zap.Int64("backlog", alert.Backlog),
+		zap.Int64("threshold", alert.Threshold),
+		zap.Time("since", alert.Since),
+	)
+}
@@ -0,0 +1,225 @@
+package qos
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultSyncBatchSize is the number of pending records fetched per tick at startup
+	DefaultSyncBatchSize = 100
+	// MinSyncBatchSize and MaxSyncBatchSize bound the adaptive batch size
+	MinSyncBatchSize = 10
+	MaxSyncBatchSize = 500
+	// DefaultSyncMaxInFlight is how many NAS devices are synced concurrently
+	DefaultSyncMaxInFlight = 4
+	// DefaultDeviceCooldown is how long a NAS is skipped after a failed sync
+	DefaultDeviceCooldown = 2 * time.Minute
+
+	// MetricsQoSSyncOverflow counts ticks that could not clear the backlog
+	MetricsQoSSyncOverflow = "qos_sync_backlog_overflow"
+	// MetricsQoSSyncBatchSize is the gauge holding the current adaptive batch size
+	MetricsQoSSyncBatchSize = "qos_sync_batch_size"
+)
+
+// deviceError is a sync failure caused by the device or the connection to
+// it, such as a dial error, a trap or a timeout. Only these put the NAS into
+// the error cooldown; configuration errors like a missing NAS or disabled
+// QoS fail their own record and the device keeps syncing.
+type deviceError struct {
+	msg string
+}
+
+func (e *deviceError) Error() string {
+	return e.msg
+}
+
+// isDeviceError reports whether a sync error should cool the NAS down
+func isDeviceError(err error) bool {
+	var devErr *deviceError
+	return errors.As(err, &devErr)
+}
+
+// syncThrottle holds the backpressure state of the periodic sync
+type syncThrottle struct {
+	batchSize   int
+	maxInFlight int
+	tickBudget  time.Duration       // Time a tick may spend before it stops picking up records
+	cooldown    time.Duration       // How long a failing NAS is skipped
+	mu          sync.Mutex          // Guards cooldowns, which workers update concurrently
+	cooldowns   map[int64]time.Time // NAS ID to the end of its error cooldown
+}
+
+func newSyncThrottle() syncThrottle {
+	return syncThrottle{
+		batchSize:   DefaultSyncBatchSize,
+		maxInFlight: DefaultSyncMaxInFlight,
+		tickBudget:  time.Minute,
+		cooldown:    DefaultDeviceCooldown,
+		cooldowns:   make(map[int64]time.Time),
+	}
+}
+
+// inCooldown reports whether a NAS is still being skipped after a failure
+func (t *syncThrottle) inCooldown(nasID int64, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	until, ok := t.cooldowns[nasID]
+	if !ok {
+		return false
+	}
+	if !now.Before(until) {
+		delete(t.cooldowns, nasID)
+		return false
+	}
+	return true
+}
+
+// cooling returns the NAS IDs still in an error cooldown, dropping expired ones
+func (t *syncThrottle) cooling(now time.Time) []int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ids := make([]int64, 0, len(t.cooldowns))
+	for nasID, until := range t.cooldowns {
+		if !now.Before(until) {
+			delete(t.cooldowns, nasID)
+			continue
+		}
+		ids = append(ids, nasID)
+	}
+	return ids
+}
+
+// startCooldown skips a NAS for the cooldown period
+func (t *syncThrottle) startCooldown(nasID int64, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cooldowns[nasID] = now.Add(t.cooldown)
+}
+
+// adjustBatch grows the batch while ticks finish quickly with backlog left
+// over and shrinks it when a tick runs out of time
+func (t *syncThrottle) adjustBatch(backlogLeft, outOfTime bool, elapsed time.Duration) {
+	switch {
+	case outOfTime:
+		t.batchSize = max(t.batchSize/2, MinSyncBatchSize)
+	case backlogLeft && elapsed < t.tickBudget/2:
+		t.batchSize = min(t.batchSize*2, MaxSyncBatchSize)
+	}
+	metrics.SetGauge(MetricsQoSSyncBatchSize, int64(t.batchSize))
+}
+
+// syncPendingQueues processes pending and failed QoS records. Records are
+// grouped by NAS so each device is synced by a single worker, at most
+// maxInFlight devices are synced at once, and the records of devices in an
+// error cooldown are left out of the fetched batch so a slow or unreachable
+// NAS with a large backlog cannot starve healthy ones.
+func (s *NasQoSService) syncPendingQueues(ctx context.Context) {
+	if s.SyncPaused() {
+		zap.L().Info("QoS sync is paused, skipping sync pass")
+		return
+	}
+	defer s.checkBacklog(ctx)
+
+	t := &s.throttle
+	batch := t.batchSize
+	start := s.now()
+	cooling := t.cooling(start)
+	pending, err := s.qosRepo.GetPending(ctx, batch, cooling)
+	if err != nil {
+		zap.L().Error("failed to get pending queues", zap.Error(err))
+		return
+	}
+
+	// Also process failed queues (with retry logic)
+	failed, err := s.qosRepo.GetFailed(ctx, max(batch/2, 1), cooling)
+	if err != nil {
+		zap.L().Error("failed to get failed queues", zap.Error(err))
+	}
+
+	records := append(pending, failed...)
+	if len(records) == 0 {
+		zap.L().Debug("no pending QoS queues to process")
+		return
+	}
+
+	deadline := start.Add(t.tickBudget)
+
+	// Group by NAS, keeping the oldest-first order within each device
+	var order []int64
+	groups := make(map[int64][]*domain.NasQoS)
+	for _, qos := range records {
+		if _, ok := groups[qos.NasID]; !ok {
+			order = append(order, qos.NasID)
+		}
+		groups[qos.NasID] = append(groups[qos.NasID], qos)
+	}
+
+	zap.L().Info("🔄 processing pending QoS queues",
+		zap.Int("count", len(records)),
+		zap.Int("devices", len(order)),
+		zap.Int("devices_in_cooldown", len(cooling)),
+	)
+
+	var processed int64
+	var outOfTime atomic.Bool
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(t.maxInFlight, 1))
+	for _, nasID := range order {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(nasID int64, queue []*domain.NasQoS) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			for _, qos := range queue {
+				if ctx.Err() != nil || !s.now().Before(deadline) {
+					outOfTime.Store(true)
+					return
+				}
+				err := s.syncQueue(ctx, qos)
+				atomic.AddInt64(&processed, 1)
+				if err == nil {
+					continue
+				}
+				if !isDeviceError(err) {
+					// The record is marked failed, the device itself is fine
+					zap.L().Warn("skipping misconfigured QoS record",
+						zap.Int64("qos_id", qos.ID),
+						zap.Int64("nas_id", nasID),
+						zap.Error(err),
+					)
+					continue
+				}
+				// Leave the rest of this device's records for a later tick
+				t.startCooldown(nasID, s.now())
+				return
+			}
+		}(nasID, groups[nasID])
+	}
+	wg.Wait()
+
+	elapsed := s.now().Sub(start)
+	backlogLeft := len(pending) >= batch || int(processed) < len(records)
+	t.adjustBatch(backlogLeft, outOfTime.Load(), elapsed)
+
+	if backlogLeft {
+		metrics.Inc(MetricsQoSSyncOverflow)
+		zap.L().Warn("QoS sync tick could not clear the backlog",
+			zap.Int("fetched", len(records)),
+			zap.Int64("processed", processed),
+			zap.Int("devices_in_cooldown", len(cooling)),
+			zap.Int("next_batch_size", t.batchSize),
+			zap.Duration("elapsed", elapsed),
+		)
+	}
+}
@@ -0,0 +1,290 @@
+package qos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/qos/clients"
+	"github.com/talkincode/toughradius/v9/pkg/metrics"
+	"gorm.io/gorm"
+)
+
+// setupDeviceClients gives every NAS its own mock client
+func setupDeviceClients(svc *NasQoSService, devices map[int64]*mockQoSClient) {
+	svc.newClient = func(_ context.Context, nas *domain.NetNas) (clients.QoSClient, error) {
+		return devices[nas.ID], nil
+	}
+}
+
+// createPendingQoS queues n pending records for a NAS
+func createPendingQoS(t *testing.T, db *gorm.DB, nasID int64, n int) {
+	for i := 0; i < n; i++ {
+		require.NoError(t, db.Create(&domain.NasQoS{
+			UserID: nasID*1000 + int64(i), NasID: nasID, QoSName: fmt.Sprintf("nas%d_user_%d", nasID, i),
+			UpRate: 1024, DownRate: 1024, Status: "pending",
+		}).Error)
+	}
+}
+
+func countQoS(t *testing.T, db *gorm.DB, nasID int64, status string) int64 {
+	var count int64
+	require.NoError(t, db.Model(&domain.NasQoS{}).Where("nas_id = ? AND status = ?", nasID, status).Count(&count).Error)
+	return count
+}
+
+func createDevices(t *testing.T, db *gorm.DB) (slow, healthy *domain.NetNas) {
+	slow = createTestNas(t, db)
+	healthy = &domain.NetNas{
+		ID:         2,
+		Name:       "mikrotik-healthy",
+		Ipaddr:     "10.0.0.2",
+		VendorCode: "14988",
+		Status:     "enabled",
+		QoSEnabled: true,
+	}
+	require.NoError(t, db.Create(healthy).Error)
+	return slow, healthy
+}
+
+func TestSyncPendingQueues_SlowDeviceDoesNotStarveHealthy(t *testing.T) {
+	require.NoError(t, metrics.InitMetrics(""))
+	svc, db, _ := setupTestService(t)
+	slow, healthy := createDevices(t, db)
+
+	slowClient := newMockQoSClient()
+	slowClient.delay = 60 * time.Millisecond
+	healthyClient := newMockQoSClient()
+	setupDeviceClients(svc, map[int64]*mockQoSClient{slow.ID: slowClient, healthy.ID: healthyClient})
+
+	createPendingQoS(t, db, slow.ID, 5)
+	createPendingQoS(t, db, healthy.ID, 5)
+
+	svc.throttle.tickBudget = 100 * time.Millisecond
+	overflowBefore := metrics.GetStore().GetCounterValue(MetricsQoSSyncOverflow)
+	svc.syncPendingQueues(context.Background())
+
+	// The healthy device is fully synced while the slow one ran out of time
+	assert.Equal(t, int64(5), countQoS(t, db, healthy.ID, "synced"))
+	slowSynced := countQoS(t, db, slow.ID, "synced")
+	assert.Greater(t, slowSynced, int64(0))
+	assert.Less(t, slowSynced, int64(5))
+	assert.Equal(t, overflowBefore+1, metrics.GetStore().GetCounterValue(MetricsQoSSyncOverflow))
+
+	// The batch shrinks after a tick that ran out of time
+	assert.Equal(t, DefaultSyncBatchSize/2, svc.throttle.batchSize)
+}
+
+func TestSyncPendingQueues_FailingDeviceCoolsDown(t *testing.T) {
+	require.NoError(t, metrics.InitMetrics(""))
+	svc, db, _ := setupTestService(t)
+	failing, healthy := createDevices(t, db)
+
+	failingClient := newMockQoSClient()
+	failingClient.err = errors.New("connection refused")
+	healthyClient := newMockQoSClient()
+	setupDeviceClients(svc, map[int64]*mockQoSClient{failing.ID: failingClient, healthy.ID: healthyClient})
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	createPendingQoS(t, db, failing.ID, 3)
+	createPendingQoS(t, db, healthy.ID, 3)
+	svc.syncPendingQueues(context.Background())
+
+	// The failing device stops after its first error
+	assert.Equal(t, 1, failingClient.tries)
+	assert.Equal(t, int64(3), countQoS(t, db, healthy.ID, "synced"))
+
+	// During the cooldown the failing device is skipped and healthy ones keep syncing
+	require.NoError(t, db.Create(&domain.NasQoS{
+		UserID: 9999, NasID: healthy.ID, QoSName: "late_user", UpRate: 1024, DownRate: 1024, Status: "pending",
+	}).Error)
+	now = now.Add(time.Minute)
+	svc.syncPendingQueues(context.Background())
+	assert.Equal(t, 1, failingClient.tries)
+	assert.Equal(t, int64(4), countQoS(t, db, healthy.ID, "synced"))
+
+	// Once the cooldown expires the device is retried
+	now = now.Add(DefaultDeviceCooldown)
+	svc.syncPendingQueues(context.Background())
+	assert.Equal(t, 2, failingClient.tries)
+}
+
+func TestSyncPendingQueues_MisconfiguredRecordDoesNotCoolDown(t *testing.T) {
+	require.NoError(t, metrics.InitMetrics(""))
+	svc, db, _ := setupTestService(t)
+	nas := createTestNas(t, db)
+	client := newMockQoSClient()
+	setupDeviceClients(svc, map[int64]*mockQoSClient{nas.ID: client})
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	// The oldest record of the device is misconfigured, the rest are fine
+	require.NoError(t, db.Create(&domain.NasQoS{
+		UserID: 9999, NasID: nas.ID, QoSName: "broken_user", UpRate: 1024, DownRate: 1024,
+		RemoteConfig: "{not json", Status: "pending", CreatedAt: now.Add(-time.Hour),
+	}).Error)
+	createPendingQoS(t, db, nas.ID, 3)
+	svc.syncPendingQueues(context.Background())
+
+	assert.Equal(t, int64(1), countQoS(t, db, nas.ID, "failed"))
+	assert.Equal(t, int64(3), countQoS(t, db, nas.ID, "synced"))
+	assert.Equal(t, 3, client.tries)
+	assert.False(t, svc.throttle.inCooldown(nas.ID, now), "a configuration error must not cool the device down")
+
+	// QoS disabled on the NAS fails its records without a cooldown either
+	require.NoError(t, db.Model(nas).Update("QoSEnabled", false).Error)
+	createPendingQoS(t, db, nas.ID, 1)
+	svc.syncPendingQueues(context.Background())
+	assert.Equal(t, 3, client.tries)
+	assert.False(t, svc.throttle.inCooldown(nas.ID, now))
+}
+
+func TestSyncThrottle_AdjustBatch(t *testing.T) {
+	require.NoError(t, metrics.InitMetrics(""))
+	th := newSyncThrottle()
+	th.tickBudget = 10 * time.Second
+
+	th.adjustBatch(true, false, time.Second)
+	assert.Equal(t, DefaultSyncBatchSize*2, th.batchSize)
+
+	th.adjustBatch(false, false, time.Second)
+	assert.Equal(t, DefaultSyncBatchSize*2, th.batchSize)
+
+	for i := 0; i < 10; i++ {
+		th.adjustBatch(true, false, time.Second)
+	}
+	assert.Equal(t, MaxSyncBatchSize, th.batchSize)
+
+	for i := 0; i < 10; i++ {
+		th.adjustBatch(true, true, 10*time.Second)
+	}
+	assert.Equal(t, MinSyncBatchSize, th.batchSize)
+	assert.Equal(t, int64(MinSyncBatchSize), metrics.GetStore().GetGaugeValue(MetricsQoSSyncBatchSize))
+}
+
+func TestSyncPendingQueues_Paused(t *testing.T) {
+	require.NoError(t, metrics.InitMetrics(""))
+	svc, db, _ := setupTestService(t)
+	nas := createTestNas(t, db)
+	client := newMockQoSClient()
+	setupDeviceClients(svc, map[int64]*mockQoSClient{nas.ID: client})
+	createPendingQoS(t, db, nas.ID, 2)
+
+	paused := true
+	svc.SetSyncPaused(func() bool { return paused })
+	svc.syncPendingQueues(context.Background())
+	assert.Zero(t, client.tries, "no device calls while paused")
+	assert.Equal(t, int64(2), countQoS(t, db, nas.ID, "pending"))
+
+	var enabled domain.NetNas
+	require.NoError(t, db.First(&enabled, nas.ID).Error)
+	assert.True(t, enabled.QoSEnabled, "pausing keeps the NAS QoS flag")
+
+	paused = false
+	svc.syncPendingQueues(context.Background())
+	assert.Equal(t, 2, client.tries)
+	assert.Equal(t, int64(2), countQoS(t, db, nas.ID, "synced"))
+}
+
+func TestGetOrCreateClient_SharedAPIHost(t *testing.T) {
+	svc, db, _ := setupTestService(t)
+	first := &domain.NetNas{ID: 1, Ipaddr: "10.0.0.1", APIHost: "192.0.2.1", VendorCode: "14988", QoSEnabled: true}
+	second := &domain.NetNas{ID: 2, Ipaddr: "10.0.0.2", APIHost: "192.0.2.1", VendorCode: "14988", QoSEnabled: true}
+	require.NoError(t, db.Create(first).Error)
+	require.NoError(t, db.Create(second).Error)
+
+	dials := 0
+	svc.newClient = func(_ context.Context, _ *domain.NetNas) (clients.QoSClient, error) {
+		dials++
+		return newMockQoSClient(), nil
+	}
+
+	a, err := svc.getOrCreateClient(context.Background(), first)
+	require.NoError(t, err)
+	b, err := svc.getOrCreateClient(context.Background(), second)
+	require.NoError(t, err)
+	assert.Same(t, a, b, "NAS records on one API host share a client")
+	assert.Equal(t, 1, dials)
+}
+
+func TestGetOrCreateClient_SlowDialDoesNotBlockOtherHosts(t *testing.T) {
+	svc, db, _ := setupTestService(t)
+	stuck, healthy := createDevices(t, db)
+
+	unblock := make(chan struct{})
+	defer close(unblock)
+	svc.newClient = func(ctx context.Context, nas *domain.NetNas) (clients.QoSClient, error) {
+		if nas.ID == stuck.ID {
+			select {
+			case <-unblock:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		return newMockQoSClient(), nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stuckErr := make(chan error, 1)
+	go func() {
+		_, err := svc.getOrCreateClient(ctx, stuck)
+		stuckErr <- err
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		_, err := svc.getOrCreateClient(context.Background(), healthy)
+		assert.NoError(t, err)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("a pending dial blocked another device")
+	}
+
+	// The pending dial gives up with its caller's context
+	cancel()
+	assert.ErrorIs(t, <-stuckErr, context.Canceled)
+}
+
+func TestSyncPendingQueues_LargeFailingBacklogDoesNotStarveHealthy(t *testing.T) {
+	require.NoError(t, metrics.InitMetrics(""))
+	svc, db, _ := setupTestService(t)
+	failing, healthy := createDevices(t, db)
+
+	failingClient := newMockQoSClient()
+	failingClient.err = errors.New("connection refused")
+	healthyClient := newMockQoSClient()
+	setupDeviceClients(svc, map[int64]*mockQoSClient{failing.ID: failingClient, healthy.ID: healthyClient})
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	// The failing device's backlog is older and larger than any batch
+	backlog := make([]*domain.NasQoS, 0, MaxSyncBatchSize+100)
+	for i := 0; i < MaxSyncBatchSize+100; i++ {
+		backlog = append(backlog, &domain.NasQoS{
+			UserID: int64(100000 + i), NasID: failing.ID, QoSName: fmt.Sprintf("backlog_%d", i),
+			UpRate: 1024, DownRate: 1024, Status: "pending", CreatedAt: now.Add(-time.Hour),
+		})
+	}
+	require.NoError(t, db.CreateInBatches(backlog, 100).Error)
+	createPendingQoS(t, db, healthy.ID, 5)
+
+	for i := 0; i < 3; i++ {
+		svc.syncPendingQueues(context.Background())
+		now = now.Add(10 * time.Second)
+	}
+
+	assert.Equal(t, 1, failingClient.tries, "the failing device is only tried once per cooldown")
+	assert.Equal(t, int64(5), countQoS(t, db, healthy.ID, "synced"))
+}
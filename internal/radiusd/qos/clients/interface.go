@@ -4,12 +4,22 @@ import "context"
 
 // QoSConfig represents generic QoS configuration for any vendor
 type QoSConfig struct {
-	Name     string // Queue/Policy name
-	UpRate   int    // Upload rate in Kbps
-	DownRate int    // Download rate in Kbps
+	Name     string                 // Queue/Policy name
+	UpRate   int                    // Upload rate in Kbps
+	DownRate int                    // Download rate in Kbps
 	Extra    map[string]interface{} // Vendor-specific extra fields
 }
 
+// QueueEntry is a normalized queue/policy as reported by a NAS device
+type QueueEntry struct {
+	ID       string `json:"id"`        // Remote queue ID
+	Name     string `json:"name"`      // Queue/Policy name
+	Target   string `json:"target"`    // Target address or interface
+	UpRate   int    `json:"up_rate"`   // Upload rate in Kbps
+	DownRate int    `json:"down_rate"` // Download rate in Kbps
+	Disabled bool   `json:"disabled"`  // Whether the queue is disabled on the device
+}
+
 // QoSClient is the interface for vendor-specific QoS clients
 // Supports multiple vendors: Mikrotik, Huawei, H3C, etc.
 type QoSClient interface {
@@ -26,6 +36,27 @@ type QoSClient interface {
 	// GetQueue retrieves queue/policy configuration from the device
 	GetQueue(ctx context.Context, remoteID string) (*QoSConfig, error)
 
+	// ListQueues retrieves all queues/policies currently on the device
+	ListQueues(ctx context.Context) ([]QueueEntry, error)
+
 	// Close closes the connection to the NAS device
 	Close() error
 }
+
+// PPPProfile is a PPP profile rate limit as reported by a NAS device
+type PPPProfile struct {
+	ID       string `json:"id"`        // Remote profile ID
+	Name     string `json:"name"`      // Profile name
+	UpRate   int    `json:"up_rate"`   // Upload rate in Kbps, 0 when unlimited
+	DownRate int    `json:"down_rate"` // Download rate in Kbps, 0 when unlimited
+}
+
+// PPPProfileClient is implemented by clients of devices that can rate limit
+// PPP sessions through their PPP profiles instead of per-user queues
+type PPPProfileClient interface {
+	// GetPPPProfile retrieves a PPP profile by name
+	GetPPPProfile(ctx context.Context, name string) (*PPPProfile, error)
+
+	// SetPPPProfileRateLimit sets the rate limit of a PPP profile
+	SetPPPProfileRateLimit(ctx context.Context, name string, upRate, downRate int) error
+}
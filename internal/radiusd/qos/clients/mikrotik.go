@@ -2,10 +2,13 @@ package clients
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-routeros/routeros/v3"
 	"github.com/go-routeros/routeros/v3/proto"
@@ -14,12 +17,18 @@ import (
 
 // MikrotikClient implements QoSClient for Mikrotik RouterOS devices
 type MikrotikClient struct {
-	client *routeros.Client
-	host   string
+	client  *routeros.Client
+	host    string
+	release func()     // Frees the device session slot held by this connection
+	mu      sync.Mutex // The sync-mode RouterOS client runs one command at a time
 }
 
-// NewMikrotikClient creates a new Mikrotik RouterOS API client
+// NewMikrotikClient creates a new Mikrotik RouterOS API client. The client
+// holds one of the device's API session slots until it is closed; waiting
+// for a free slot and dialing stop when ctx is done or after
+// SessionAcquireTimeout.
 // Parameters:
+//   - ctx: Bounds the wait for a session slot and the dial
 //   - host: RouterOS device IP address or hostname
 //   - username: API username
 //   - password: API password
@@ -28,15 +37,23 @@ type MikrotikClient struct {
 // Returns:
 //   - *MikrotikClient: Initialized client ready for use
 //   - error: Connection or authentication error
-func NewMikrotikClient(host, username, password string, port int) (*MikrotikClient, error) {
+func NewMikrotikClient(ctx context.Context, host, username, password string, port int) (*MikrotikClient, error) {
 	if port <= 0 {
 		port = 8728 // Default RouterOS API port
 	}
 
 	addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
 
-	client, err := routeros.Dial(addr, username, password)
+	ctx, cancel := context.WithTimeout(ctx, SessionAcquireTimeout)
+	defer cancel()
+
+	release, err := RouterOSSessions().Acquire(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("mikrotik connection failed: no free API session: %w", err)
+	}
+	client, err := routeros.DialContext(ctx, addr, username, password)
 	if err != nil {
+		release()
 		zap.L().Error("failed to connect to Mikrotik",
 			zap.String("host", host),
 			zap.Int("port", port),
@@ -51,11 +68,28 @@ func NewMikrotikClient(host, username, password string, port int) (*MikrotikClie
 	)
 
 	return &MikrotikClient{
-		client: client,
-		host:   host,
+		client:  client,
+		host:    host,
+		release: release,
 	}, nil
 }
 
+// run executes a command on the connection, one at a time
+func (c *MikrotikClient) run(ctx context.Context, args []string) (*routeros.Reply, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	reply, err := c.client.RunArgsContext(ctx, args)
+
+	var deviceErr *routeros.DeviceError
+	if errors.As(err, &deviceErr) {
+		return nil, newTrapError(deviceErr)
+	}
+	return reply, err
+}
+
 // CreateQueue creates a simple queue on Mikrotik RouterOS
 // Uses /queue/simple command with max-limit parameter
 // Format of max-limit: "uploadKbps/downloadKbps" (e.g., "1024k/2048k")
@@ -82,6 +116,12 @@ func (c *MikrotikClient) CreateQueue(ctx context.Context, config *QoSConfig) (st
 		fmt.Sprintf("=max-limit=%s", maxLimit),
 	}
 
+	burst, err := burstArgs(config.Extra, false)
+	if err != nil {
+		return "", err
+	}
+	args = append(args, burst...)
+
 	// Add target if specified in extra config
 	if config.Extra != nil {
 		if target, ok := config.Extra["target"].(string); ok && target != "" {
@@ -90,16 +130,13 @@ func (c *MikrotikClient) CreateQueue(ctx context.Context, config *QoSConfig) (st
 	}
 
 	// Execute /queue/simple/add command
-	reply, err := c.client.RunArgs(args)
+	reply, err := c.run(ctx, args)
 	if err != nil {
 		return "", fmt.Errorf("create queue error: %w", err)
 	}
 
 	// Extract queue ID from done sentence
-	queueID := ""
-	if reply.Done != nil {
-		queueID = reply.Done.Map[".id"]
-	}
+	queueID := NewRouterOSEntry(reply.Done).GetString(".id")
 
 	if queueID == "" {
 		return "", fmt.Errorf("no queue ID returned from Mikrotik")
@@ -126,7 +163,7 @@ func (c *MikrotikClient) DeleteQueue(ctx context.Context, remoteID string) error
 		fmt.Sprintf("=.id=%s", remoteID),
 	}
 
-	_, err := c.client.RunArgs(args)
+	_, err := c.run(ctx, args)
 	if err != nil {
 		return fmt.Errorf("delete queue error: %w", err)
 	}
@@ -156,7 +193,14 @@ func (c *MikrotikClient) UpdateQueue(ctx context.Context, remoteID string, confi
 		fmt.Sprintf("=max-limit=%s", maxLimit),
 	}
 
-	_, err := c.client.RunArgs(args)
+	// Reset bursts missing from Extra, which may have been removed since the queue was created
+	burst, err := burstArgs(config.Extra, true)
+	if err != nil {
+		return err
+	}
+	args = append(args, burst...)
+
+	_, err = c.run(ctx, args)
 	if err != nil {
 		return fmt.Errorf("update queue error: %w", err)
 	}
@@ -179,7 +223,7 @@ func (c *MikrotikClient) GetQueue(ctx context.Context, remoteID string) (*QoSCon
 		fmt.Sprintf("?.id=%s", remoteID),
 	}
 
-	reply, err := c.client.RunArgs(args)
+	reply, err := c.run(ctx, args)
 	if err != nil {
 		return nil, fmt.Errorf("get queue error: %w", err)
 	}
@@ -192,8 +236,67 @@ func (c *MikrotikClient) GetQueue(ctx context.Context, remoteID string) (*QoSCon
 	return config, nil
 }
 
-// Close closes the connection to Mikrotik RouterOS
+// ListQueues retrieves all simple queues from Mikrotik
+func (c *MikrotikClient) ListQueues(ctx context.Context) ([]QueueEntry, error) {
+	reply, err := c.run(ctx, []string{"/queue/simple/print"})
+	if err != nil {
+		return nil, fmt.Errorf("list queues error: %w", err)
+	}
+
+	entries := make([]QueueEntry, 0, len(reply.Re))
+	for _, sentence := range reply.Re {
+		entries = append(entries, parseQueueEntry(sentence))
+	}
+	return entries, nil
+}
+
+// GetPPPProfile retrieves a PPP profile and its rate-limit from Mikrotik
+func (c *MikrotikClient) GetPPPProfile(ctx context.Context, name string) (*PPPProfile, error) {
+	if name == "" {
+		return nil, fmt.Errorf("profile name is required")
+	}
+
+	reply, err := c.run(ctx, []string{
+		"/ppp/profile/print",
+		fmt.Sprintf("?name=%s", name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get ppp profile error: %w", err)
+	}
+
+	if len(reply.Re) == 0 {
+		return nil, fmt.Errorf("ppp profile not found: %s", name)
+	}
+
+	return parsePPPProfile(reply.Re[0]), nil
+}
+
+// SetPPPProfileRateLimit sets the rate-limit of a PPP profile on Mikrotik.
+// Sessions pick up the new limit when they reconnect.
+func (c *MikrotikClient) SetPPPProfileRateLimit(ctx context.Context, name string, upRate, downRate int) error {
+	args, err := pppProfileSetArgs(name, upRate, downRate)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.run(ctx, args); err != nil {
+		return fmt.Errorf("set ppp profile error: %w", err)
+	}
+
+	zap.L().Info("ppp profile rate limit updated",
+		zap.String("profile", name),
+		zap.Int("up_rate", upRate),
+		zap.Int("down_rate", downRate),
+	)
+
+	return nil
+}
+
+// Close closes the connection to Mikrotik RouterOS and frees its session slot
 func (c *MikrotikClient) Close() error {
+	if c.release != nil {
+		defer c.release()
+	}
 	if c.client != nil {
 		err := c.client.Close()
 		zap.L().Info("Mikrotik connection closed", zap.String("host", c.host))
@@ -204,35 +307,235 @@ func (c *MikrotikClient) Close() error {
 
 // Helper functions
 
-func parseQueueResponse(sentence *proto.Sentence) *QoSConfig {
-	config := &QoSConfig{Extra: make(map[string]interface{})}
+// parseQueueEntry normalizes a /queue/simple/print sentence
+func parseQueueEntry(sentence *proto.Sentence) QueueEntry {
+	config := parseQueueResponse(sentence)
+	reply := NewRouterOSEntry(sentence)
+	return QueueEntry{
+		ID:       reply.GetString(".id"),
+		Name:     config.Name,
+		Target:   reply.GetString("target"),
+		UpRate:   config.UpRate,
+		DownRate: config.DownRate,
+		Disabled: reply.GetBool("disabled"),
+	}
+}
 
-	// Parse from Map
-	if sentence.Map != nil {
-		if name, ok := sentence.Map["name"]; ok {
-			config.Name = name
-		}
+// pppProfileSetArgs builds the /ppp/profile/set command for a rate limit.
+// The RouterOS rate-limit is "rx/tx" from the client's point of view, which
+// is upload/download; an empty rate-limit removes the limit.
+func pppProfileSetArgs(name string, upRate, downRate int) ([]string, error) {
+	if name == "" {
+		return nil, fmt.Errorf("profile name is required")
+	}
+	if upRate < 0 || downRate < 0 {
+		return nil, fmt.Errorf("profile rates cannot be negative")
+	}
+
+	rateLimit := ""
+	if upRate > 0 || downRate > 0 {
+		rateLimit = fmt.Sprintf("%dk/%dk", upRate, downRate)
+	}
+
+	return []string{
+		"/ppp/profile/set",
+		fmt.Sprintf("=numbers=%s", name),
+		fmt.Sprintf("=rate-limit=%s", rateLimit),
+	}, nil
+}
+
+// parsePPPProfile normalizes a /ppp/profile/print sentence. Only the leading
+// rx/tx pair of the rate-limit is used, burst and priority fields are ignored.
+func parsePPPProfile(sentence *proto.Sentence) *PPPProfile {
+	reply := NewRouterOSEntry(sentence)
+	profile := &PPPProfile{
+		ID:   reply.GetString(".id"),
+		Name: reply.GetString("name"),
+	}
+	if up, down, ok := reply.GetRate("rate-limit"); ok {
+		profile.UpRate = up
+		profile.DownRate = down
+	}
+	return profile
+}
+
+// Extra keys holding RouterOS burst parameters in "up/down" notation,
+// e.g. "2048k/4096k" for limits and thresholds or "8s/8s" for burst time
+const (
+	ExtraBurstLimit     = "burst_limit"
+	ExtraBurstThreshold = "burst_threshold"
+	ExtraBurstTime      = "burst_time"
+)
 
-		// Parse max-limit: "1024k/2048k" format
-		if maxLimit, ok := sentence.Map["max-limit"]; ok {
-			parts := strings.Split(maxLimit, "/")
-			if len(parts) == 2 {
-				// Remove 'k' suffix and parse
-				upStr := strings.TrimSuffix(parts[0], "k")
-				downStr := strings.TrimSuffix(parts[1], "k")
-
-				if up, err := strconv.Atoi(upStr); err == nil {
-					config.UpRate = up
-				}
-				if down, err := strconv.Atoi(downStr); err == nil {
-					config.DownRate = down
-				}
+// burstArgs converts burst parameters from Extra into /queue/simple arguments.
+// With reset, parameters missing from Extra are sent as the zero values
+// RouterOS uses for no burst, so a burst removed from the config is cleared
+// on the device.
+func burstArgs(extra map[string]interface{}, reset bool) ([]string, error) {
+	var args []string
+
+	for _, key := range []struct{ extra, attr string }{
+		{ExtraBurstLimit, "burst-limit"},
+		{ExtraBurstThreshold, "burst-threshold"},
+	} {
+		value, ok := extra[key.extra].(string)
+		if !ok || value == "" {
+			if reset {
+				args = append(args, fmt.Sprintf("=%s=0/0", key.attr))
 			}
+			continue
 		}
+		up, down, err := parseRatePair(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key.attr, err)
+		}
+		args = append(args, fmt.Sprintf("=%s=%dk/%dk", key.attr, up, down))
+	}
+
+	if value, ok := extra[ExtraBurstTime].(string); ok && value != "" {
+		up, down, err := parseBurstTime(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid burst-time: %w", err)
+		}
+		args = append(args, fmt.Sprintf("=burst-time=%ds/%ds", up, down))
+	} else if reset {
+		args = append(args, "=burst-time=0s/0s")
+	}
+
+	return args, nil
+}
 
-		if target, ok := sentence.Map["target"]; ok {
-			config.Extra["target"] = target
+// NormalizeBurst rewrites a burst value from Extra into the notation a queue
+// read from the device uses, so both sides compare equal. Zero values become
+// empty as the device omits them; unparsable values are returned unchanged.
+func NormalizeBurst(key, value string) string {
+	if value == "" {
+		return ""
+	}
+	if key == ExtraBurstTime {
+		up, down, err := parseBurstTime(value)
+		switch {
+		case err != nil:
+			return value
+		case up == 0 && down == 0:
+			return ""
 		}
+		return fmt.Sprintf("%ds/%ds", up, down)
+	}
+
+	up, down, err := parseRatePair(value)
+	switch {
+	case err != nil:
+		return value
+	case up == 0 && down == 0:
+		return ""
+	}
+	return fmt.Sprintf("%dk/%dk", up, down)
+}
+
+// parseRatePair parses an "up/down" RouterOS rate pair into Kbps
+func parseRatePair(value string) (int, int, error) {
+	parts := strings.Split(strings.TrimSpace(value), "/")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expected up/down rate, got %q", value)
+	}
+	up, err := parseRouterOSRate(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	down, err := parseRouterOSRate(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return up, down, nil
+}
+
+// parseRouterOSRate converts a RouterOS rate ("512k", "2M", "1G" or plain
+// bits per second as printed by the device) into Kbps
+func parseRouterOSRate(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("empty rate")
+	}
+
+	multiplier := 0 // plain bits per second
+	switch value[len(value)-1] {
+	case 'k', 'K':
+		multiplier = 1
+	case 'M':
+		multiplier = 1000
+	case 'G':
+		multiplier = 1000 * 1000
+	}
+	if multiplier > 0 {
+		value = value[:len(value)-1]
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid rate %q", value)
+	}
+	if multiplier == 0 {
+		return n / 1000, nil
+	}
+	return n * multiplier, nil
+}
+
+// parseBurstTime parses a RouterOS burst-time ("8s/8s", "8/8" or "8s") into seconds
+func parseBurstTime(value string) (int, int, error) {
+	parts := strings.Split(strings.TrimSpace(value), "/")
+	if len(parts) == 1 {
+		parts = append(parts, parts[0])
+	}
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid burst time %q", value)
+	}
+
+	seconds := make([]int, 2)
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if n, err := strconv.Atoi(part); err == nil && n >= 0 {
+			seconds[i] = n
+			continue
+		}
+		d, err := time.ParseDuration(part)
+		if err != nil || d < 0 {
+			return 0, 0, fmt.Errorf("invalid burst time %q", value)
+		}
+		seconds[i] = int(d.Seconds())
+	}
+	return seconds[0], seconds[1], nil
+}
+
+func parseQueueResponse(sentence *proto.Sentence) *QoSConfig {
+	config := &QoSConfig{Extra: make(map[string]interface{})}
+	reply := NewRouterOSEntry(sentence)
+
+	config.Name = reply.GetString("name")
+
+	// Parse max-limit: "1024k/2048k" or plain bits per second
+	if up, down, ok := reply.GetRate("max-limit"); ok {
+		config.UpRate = up
+		config.DownRate = down
+	}
+
+	// Burst parameters are kept in Extra using the "up/down" notation
+	for _, key := range []struct{ attr, extra string }{
+		{"burst-limit", ExtraBurstLimit},
+		{"burst-threshold", ExtraBurstThreshold},
+	} {
+		if up, down, ok := reply.GetRate(key.attr); ok && (up > 0 || down > 0) {
+			config.Extra[key.extra] = fmt.Sprintf("%dk/%dk", up, down)
+		}
+	}
+	if value, ok := reply.Lookup("burst-time"); ok {
+		if up, down, err := parseBurstTime(value); err == nil && (up > 0 || down > 0) {
+			config.Extra[ExtraBurstTime] = fmt.Sprintf("%ds/%ds", up, down)
+		}
+	}
+
+	if target, ok := reply.Lookup("target"); ok {
+		config.Extra["target"] = target
 	}
 
 	return config
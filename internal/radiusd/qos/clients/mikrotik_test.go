@@ -0,0 +1,192 @@
+package clients
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-routeros/routeros/v3"
+	"github.com/go-routeros/routeros/v3/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQueueResponseWithBurst(t *testing.T) {
+	sentence := &proto.Sentence{Map: map[string]string{
+		"name":            "user_42",
+		"target":          "10.0.0.42/32",
+		"max-limit":       "1024000/2048000",
+		"burst-limit":     "2M/4M",
+		"burst-threshold": "768k/1536k",
+		"burst-time":      "8s/16s",
+	}}
+
+	config := parseQueueResponse(sentence)
+
+	assert.Equal(t, "user_42", config.Name)
+	assert.Equal(t, 1024, config.UpRate)
+	assert.Equal(t, 2048, config.DownRate)
+	assert.Equal(t, "10.0.0.42/32", config.Extra["target"])
+	assert.Equal(t, "2000k/4000k", config.Extra[ExtraBurstLimit])
+	assert.Equal(t, "768k/1536k", config.Extra[ExtraBurstThreshold])
+	assert.Equal(t, "8s/16s", config.Extra[ExtraBurstTime])
+
+	// Reconstructing the queue yields the same burst policy
+	args, err := burstArgs(config.Extra, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"=burst-limit=2000k/4000k",
+		"=burst-threshold=768k/1536k",
+		"=burst-time=8s/16s",
+	}, args)
+}
+
+func TestParseQueueResponseWithoutBurst(t *testing.T) {
+	sentence := &proto.Sentence{Map: map[string]string{
+		"name":            "user_7",
+		"max-limit":       "512k/1024k",
+		"burst-limit":     "0/0",
+		"burst-threshold": "0/0",
+		"burst-time":      "0s/0s",
+	}}
+
+	config := parseQueueResponse(sentence)
+
+	assert.Equal(t, 512, config.UpRate)
+	assert.Equal(t, 1024, config.DownRate)
+	assert.NotContains(t, config.Extra, ExtraBurstLimit)
+	assert.NotContains(t, config.Extra, ExtraBurstThreshold)
+	assert.NotContains(t, config.Extra, ExtraBurstTime)
+
+	args, err := burstArgs(config.Extra, false)
+	require.NoError(t, err)
+	assert.Empty(t, args)
+
+	// An update clears bursts that are no longer configured
+	args, err = burstArgs(config.Extra, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"=burst-limit=0/0", "=burst-threshold=0/0", "=burst-time=0s/0s"}, args)
+}
+
+func TestBurstArgsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		extra   map[string]interface{}
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "Single burst time applies to both directions",
+			extra: map[string]interface{}{ExtraBurstTime: "10"},
+			want:  []string{"=burst-time=10s/10s"},
+		},
+		{
+			name:    "Malformed burst limit",
+			extra:   map[string]interface{}{ExtraBurstLimit: "fast"},
+			wantErr: true,
+		},
+		{
+			name:    "Malformed burst time",
+			extra:   map[string]interface{}{ExtraBurstTime: "soon/later"},
+			wantErr: true,
+		},
+		{
+			name:  "Nil extra",
+			extra: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := burstArgs(tt.extra, false)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, args)
+		})
+	}
+}
+
+func TestParsePPPProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		sentence map[string]string
+		want     PPPProfile
+	}{
+		{
+			name: "Rate limit with burst",
+			sentence: map[string]string{
+				".id":        "*2",
+				"name":       "plan-10m",
+				"rate-limit": "5M/10M 8M/16M 4M/8M 8/8 8 2M/4M",
+			},
+			want: PPPProfile{ID: "*2", Name: "plan-10m", UpRate: 5000, DownRate: 10000},
+		},
+		{
+			name: "Plain rate limit",
+			sentence: map[string]string{
+				".id":        "*3",
+				"name":       "plan-2m",
+				"rate-limit": "1024k/2048k",
+			},
+			want: PPPProfile{ID: "*3", Name: "plan-2m", UpRate: 1024, DownRate: 2048},
+		},
+		{
+			name:     "Unlimited profile",
+			sentence: map[string]string{".id": "*0", "name": "default"},
+			want:     PPPProfile{ID: "*0", Name: "default"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := parsePPPProfile(&proto.Sentence{Map: tt.sentence})
+			assert.Equal(t, tt.want, *profile)
+		})
+	}
+}
+
+func TestPPPProfileSetArgs(t *testing.T) {
+	args, err := pppProfileSetArgs("plan-10m", 5000, 10000)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/ppp/profile/set",
+		"=numbers=plan-10m",
+		"=rate-limit=5000k/10000k",
+	}, args)
+
+	// Zero rates remove the limit
+	args, err = pppProfileSetArgs("plan-10m", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "=rate-limit=", args[2])
+
+	_, err = pppProfileSetArgs("", 1024, 1024)
+	assert.Error(t, err)
+	_, err = pppProfileSetArgs("plan-10m", -1, 1024)
+	assert.Error(t, err)
+}
+
+func TestNewTrapError(t *testing.T) {
+	trap := newTrapError(&routeros.DeviceError{Sentence: &proto.Sentence{
+		Word: "!trap",
+		Map:  map[string]string{"category": "4", "message": "failure: already have queue with this name"},
+	}})
+	assert.Equal(t, "general failure: failure: already have queue with this name", trap.Error())
+
+	fatal := newTrapError(&routeros.DeviceError{Sentence: &proto.Sentence{
+		Word: "!fatal",
+		Map:  map[string]string{"message": "session terminated on request"},
+	}})
+	assert.Equal(t, "fatal: session terminated on request", fatal.Error())
+
+	noCategory := newTrapError(&routeros.DeviceError{Sentence: &proto.Sentence{
+		Word: "!trap",
+		Map:  map[string]string{"message": "no such item"},
+	}})
+	assert.Equal(t, "no such item", noCategory.Error())
+
+	wrapped := fmt.Errorf("create queue error: %w", trap)
+	assert.Equal(t, trap.Error(), TrapMessage(wrapped))
+	assert.Empty(t, TrapMessage(fmt.Errorf("dial tcp: connection refused")))
+}
@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-routeros/routeros/v3/proto"
+)
+
+// RouterOSEntry holds the attributes of a RouterOS reply sentence under
+// normalized keys: lower case, dashes instead of underscores, and ".id" for
+// the item ID whether it is asked for as "id" or ".id"
+type RouterOSEntry map[string]string
+
+// NewRouterOSEntry normalizes the attributes of a reply sentence. A nil
+// sentence gives an empty entry.
+func NewRouterOSEntry(sentence *proto.Sentence) RouterOSEntry {
+	entry := make(RouterOSEntry)
+	if sentence == nil {
+		return entry
+	}
+	for key, value := range sentence.Map {
+		entry[normalizeRouterOSKey(key)] = value
+	}
+	return entry
+}
+
+func normalizeRouterOSKey(key string) string {
+	key = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "_", "-")
+	if key == "id" {
+		return ".id"
+	}
+	return key
+}
+
+// Lookup returns the value of key and whether the device sent it
+func (e RouterOSEntry) Lookup(key string) (string, bool) {
+	value, ok := e[normalizeRouterOSKey(key)]
+	return value, ok
+}
+
+// GetString returns the value of key, or "" when it is missing
+func (e RouterOSEntry) GetString(key string) string {
+	value, _ := e.Lookup(key)
+	return value
+}
+
+// GetInt returns key as an integer, ok is false when it is missing or not a number
+func (e RouterOSEntry) GetInt(key string) (int, bool) {
+	value, found := e.Lookup(key)
+	if !found {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	return n, err == nil
+}
+
+// GetBool reports whether key is "true" or "yes", as RouterOS prints flags
+func (e RouterOSEntry) GetBool(key string) bool {
+	switch strings.ToLower(e.GetString(key)) {
+	case "true", "yes":
+		return true
+	}
+	return false
+}
+
+// GetRate parses key as an "up/down" rate pair in Kbps. Only the leading
+// pair is used, so the burst and priority fields of a PPP rate-limit are
+// ignored. ok is false when the key is missing or not a rate pair.
+func (e RouterOSEntry) GetRate(key string) (up, down int, ok bool) {
+	fields := strings.Fields(e.GetString(key))
+	if len(fields) == 0 {
+		return 0, 0, false
+	}
+	up, down, err := parseRatePair(fields[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	return up, down, true
+}
@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/go-routeros/routeros/v3/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterOSEntryAccessors(t *testing.T) {
+	entry := NewRouterOSEntry(&proto.Sentence{Map: map[string]string{
+		".id":         "*1A",
+		"name":        "pppoe-alice",
+		"max-limit":   "1024000/2048000",
+		"rate-limit":  "2M/4M 4M/8M 1M/2M 8/8 5",
+		"burst-time":  "8s/8s",
+		"disabled":    "true",
+		"dynamic":     "no",
+		"bytes-total": "12345",
+		"comment":     "",
+	}})
+
+	// Item ID and dash/underscore spellings resolve to the same key
+	assert.Equal(t, "*1A", entry.GetString(".id"))
+	assert.Equal(t, "*1A", entry.GetString("id"))
+	assert.Equal(t, "1024000/2048000", entry.GetString("max_limit"))
+	assert.Equal(t, "pppoe-alice", entry.GetString("Name"))
+
+	value, ok := entry.Lookup("comment")
+	assert.True(t, ok)
+	assert.Empty(t, value)
+	_, ok = entry.Lookup("target")
+	assert.False(t, ok)
+	assert.Empty(t, entry.GetString("target"))
+
+	n, ok := entry.GetInt("bytes_total")
+	assert.True(t, ok)
+	assert.Equal(t, 12345, n)
+	_, ok = entry.GetInt("name")
+	assert.False(t, ok)
+	_, ok = entry.GetInt("missing")
+	assert.False(t, ok)
+
+	assert.True(t, entry.GetBool("disabled"))
+	assert.False(t, entry.GetBool("dynamic"))
+	assert.False(t, entry.GetBool("missing"))
+
+	up, down, ok := entry.GetRate("max-limit")
+	assert.True(t, ok)
+	assert.Equal(t, [2]int{1024, 2048}, [2]int{up, down})
+	up, down, ok = entry.GetRate("rate_limit")
+	assert.True(t, ok, "only the leading pair of a rate-limit is read")
+	assert.Equal(t, [2]int{2000, 4000}, [2]int{up, down})
+	_, _, ok = entry.GetRate("name")
+	assert.False(t, ok)
+	_, _, ok = entry.GetRate("missing")
+	assert.False(t, ok)
+}
+
+func TestNewRouterOSEntryNil(t *testing.T) {
+	entry := NewRouterOSEntry(nil)
+	assert.Empty(t, entry)
+	assert.Empty(t, entry.GetString(".id"))
+	assert.Empty(t, NewRouterOSEntry(&proto.Sentence{}))
+}
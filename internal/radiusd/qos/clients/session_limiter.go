@@ -0,0 +1,85 @@
+package clients
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultRouterOSMaxSessions is how many API sessions may be active on one
+// RouterOS device at the same time unless configured otherwise
+const DefaultRouterOSMaxSessions = 1
+
+// SessionAcquireTimeout bounds how long a new connection waits for a free
+// session slot, so a device whose sessions are all held fails instead of
+// blocking its caller
+const SessionAcquireTimeout = 30 * time.Second
+
+// SessionLimiter caps the number of concurrent API sessions per device.
+// RouterOS only accepts a limited number of API sessions, so every caller
+// that talks to a device (QoS sync, live queue reads, PPP profile pushes)
+// takes a slot for the host before dialing and holds it until the
+// connection is closed.
+type SessionLimiter struct {
+	mu    sync.Mutex
+	limit func() int
+	hosts map[string]chan struct{}
+}
+
+// NewSessionLimiter creates a limiter allowing limit sessions per host
+func NewSessionLimiter(limit int) *SessionLimiter {
+	l := &SessionLimiter{hosts: make(map[string]chan struct{})}
+	l.SetLimit(func() int { return limit })
+	return l
+}
+
+// SetLimit sets the function returning the per-host session limit. It is
+// evaluated on every Acquire so configuration changes apply without a restart;
+// values below 1 fall back to DefaultRouterOSMaxSessions.
+func (l *SessionLimiter) SetLimit(limit func() int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = limit
+}
+
+// slots returns the semaphore of a host, replacing it when the limit changed.
+// Sessions still holding a slot of a replaced semaphore release into it.
+func (l *SessionLimiter) slots(host string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := DefaultRouterOSMaxSessions
+	if l.limit != nil {
+		if n := l.limit(); n > 0 {
+			limit = n
+		}
+	}
+
+	sem, ok := l.hosts[host]
+	if !ok || cap(sem) != limit {
+		sem = make(chan struct{}, limit)
+		l.hosts[host] = sem
+	}
+	return sem
+}
+
+// Acquire waits for a free session slot on host. The returned release func
+// must be called once the session is no longer in use.
+func (l *SessionLimiter) Acquire(ctx context.Context, host string) (func(), error) {
+	sem := l.slots(host)
+	select {
+	case sem <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-sem }) }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// routerOSSessions is shared by every RouterOS client of the process
+var routerOSSessions = NewSessionLimiter(DefaultRouterOSMaxSessions)
+
+// RouterOSSessions returns the process-wide RouterOS session limiter
+func RouterOSSessions() *SessionLimiter {
+	return routerOSSessions
+}
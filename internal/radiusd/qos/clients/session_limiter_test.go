@@ -0,0 +1,130 @@
+package clients
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runSessions runs n concurrent sessions against host and returns the
+// highest number that held a slot at the same time
+func runSessions(t *testing.T, l *SessionLimiter, host string, n int) int64 {
+	t.Helper()
+
+	var active, peak int64
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := l.Acquire(context.Background(), host)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer release()
+
+			cur := atomic.AddInt64(&active, 1)
+			for {
+				old := atomic.LoadInt64(&peak)
+				if cur <= old || atomic.CompareAndSwapInt64(&peak, old, cur) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt64(&active, -1)
+		}()
+	}
+	wg.Wait()
+	return peak
+}
+
+func TestSessionLimiterCapsConcurrentSessionsPerHost(t *testing.T) {
+	for _, limit := range []int{1, 3} {
+		l := NewSessionLimiter(limit)
+		assert.Equal(t, int64(limit), runSessions(t, l, "10.0.0.1", 12), "limit %d", limit)
+	}
+}
+
+func TestSessionLimiterDefaultsInvalidLimit(t *testing.T) {
+	l := NewSessionLimiter(0)
+	assert.Equal(t, int64(DefaultRouterOSMaxSessions), runSessions(t, l, "10.0.0.1", 6))
+}
+
+func TestSessionLimiterHostsAreIndependent(t *testing.T) {
+	l := NewSessionLimiter(1)
+
+	release, err := l.Acquire(context.Background(), "10.0.0.1")
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	other, err := l.Acquire(ctx, "10.0.0.2")
+	require.NoError(t, err, "a busy device must not block other devices")
+	other()
+}
+
+func TestSessionLimiterAcquireHonorsContext(t *testing.T) {
+	l := NewSessionLimiter(1)
+
+	release, err := l.Acquire(context.Background(), "10.0.0.1")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx, "10.0.0.1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Releasing twice must not free a second slot
+	release()
+	release()
+	again, err := l.Acquire(context.Background(), "10.0.0.1")
+	require.NoError(t, err)
+	defer again()
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel2()
+	_, err = l.Acquire(ctx2, "10.0.0.1")
+	assert.Error(t, err)
+}
+
+func TestSessionLimiterAppliesLimitChanges(t *testing.T) {
+	l := NewSessionLimiter(1)
+	assert.Equal(t, int64(1), runSessions(t, l, "10.0.0.1", 6))
+
+	l.SetLimit(func() int { return 2 })
+	assert.Equal(t, int64(2), runSessions(t, l, "10.0.0.1", 6))
+}
+
+func TestMikrotikClientHoldsSessionUntilClose(t *testing.T) {
+	l := NewSessionLimiter(1)
+
+	release, err := l.Acquire(context.Background(), "10.0.0.1")
+	require.NoError(t, err)
+	client := &MikrotikClient{host: "10.0.0.1", release: release}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx, "10.0.0.1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "an open client keeps its slot")
+
+	require.NoError(t, client.Close())
+	require.NoError(t, client.Close())
+	assert.Equal(t, int64(1), runSessions(t, l, "10.0.0.1", 4), "closing twice frees a single slot")
+}
+
+func TestNewMikrotikClientGivesUpWithoutFreeSession(t *testing.T) {
+	release, err := RouterOSSessions().Acquire(context.Background(), "192.0.2.10")
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = NewMikrotikClient(ctx, "192.0.2.10", "admin", "secret", 8728)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
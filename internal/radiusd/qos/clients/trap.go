@@ -0,0 +1,69 @@
+package clients
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-routeros/routeros/v3"
+)
+
+// routerOSTrapCategories names the category codes of a RouterOS !trap reply
+var routerOSTrapCategories = map[string]string{
+	"0": "missing item or command",
+	"1": "argument value failure",
+	"2": "command execution interrupted",
+	"3": "scripting failure",
+	"4": "general failure",
+	"5": "API failure",
+	"6": "TTY failure",
+	"7": "return value",
+}
+
+// TrapError is an error reported by a RouterOS device in a !trap or !fatal
+// reply, keeping the device's own category and message
+type TrapError struct {
+	Category string // Category name, "fatal" for a !fatal reply
+	Message  string // Message exactly as sent by the device
+}
+
+func (e *TrapError) Error() string {
+	if e.Category == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Category, e.Message)
+}
+
+// newTrapError converts a go-routeros device error into a TrapError
+func newTrapError(err *routeros.DeviceError) *TrapError {
+	trap := &TrapError{}
+	if err.Sentence == nil {
+		trap.Message = err.Error()
+		return trap
+	}
+
+	reply := NewRouterOSEntry(err.Sentence)
+	trap.Message = reply.GetString("message")
+	if trap.Message == "" {
+		trap.Message = err.Sentence.String()
+	}
+	if err.Sentence.Word == "!fatal" {
+		trap.Category = "fatal"
+	} else if code, ok := reply.Lookup("category"); ok {
+		if name, known := routerOSTrapCategories[code]; known {
+			trap.Category = name
+		} else {
+			trap.Category = "category " + code
+		}
+	}
+	return trap
+}
+
+// TrapMessage returns the full device error carried by err, or "" when err
+// did not come from the device
+func TrapMessage(err error) string {
+	var trap *TrapError
+	if errors.As(err, &trap) {
+		return trap.Error()
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package qos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/qos/clients"
+)
+
+// Plan actions, matching what a sync of the record would do
+const (
+	PlanActionCreate = "create" // The queue does not exist on the device yet
+	PlanActionUpdate = "update" // The desired config differs from the last synced one
+	PlanActionNone   = "none"   // A sync would leave the device untouched
+)
+
+// QueueSettings are the queue parameters compared by a plan
+type QueueSettings struct {
+	Name           string `json:"name"`
+	UpRate         int    `json:"up_rate"`
+	DownRate       int    `json:"down_rate"`
+	BurstLimit     string `json:"burst_limit,omitempty"`
+	BurstThreshold string `json:"burst_threshold,omitempty"`
+	BurstTime      string `json:"burst_time,omitempty"`
+}
+
+// QueueChange is a single setting that differs between device and desired config
+type QueueChange struct {
+	Field   string      `json:"field"`
+	Current interface{} `json:"current"`
+	Desired interface{} `json:"desired"`
+}
+
+// QueuePlan previews what syncing a QoS record would change on its device
+type QueuePlan struct {
+	QoSID    int64          `json:"qos_id,string"`
+	NasID    int64          `json:"nas_id,string"`
+	RemoteID string         `json:"remote_id"`
+	Action   string         `json:"action"`
+	Current  *QueueSettings `json:"current"` // nil when the queue does not exist on the device
+	Desired  *QueueSettings `json:"desired"`
+	Changes  []QueueChange  `json:"changes"`
+}
+
+// PlanQueue reads the queue of a QoS record from its device and compares it
+// with the desired configuration without writing anything. Changes lists
+// every setting that differs from the device, even when Action is none
+// because the record's config already matches the last sync.
+func (s *NasQoSService) PlanQueue(ctx context.Context, qos *domain.NasQoS) (*QueuePlan, error) {
+	nas := &domain.NetNas{}
+	if err := s.db.First(nas, qos.NasID).Error; err != nil {
+		return nil, fmt.Errorf("NAS not found: %w", err)
+	}
+	if !nas.QoSEnabled {
+		return nil, fmt.Errorf("QoS disabled on NAS %s", nas.Ipaddr)
+	}
+
+	plan := &QueuePlan{
+		QoSID:    qos.ID,
+		NasID:    qos.NasID,
+		RemoteID: qos.RemoteID,
+		Desired:  queueSettings(queueConfig(qos)),
+	}
+
+	switch {
+	case qos.RemoteID == "":
+		plan.Action = PlanActionCreate
+	case qosConfigChanged(qos):
+		plan.Action = PlanActionUpdate
+	default:
+		plan.Action = PlanActionNone
+	}
+
+	if qos.RemoteID != "" {
+		client, err := s.getOrCreateClient(ctx, nas)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client: %w", err)
+		}
+		current, err := client.GetQueue(ctx, qos.RemoteID)
+		if err != nil {
+			s.dropClient(nas)
+			return nil, fmt.Errorf("read queue failed: %w", err)
+		}
+		if current != nil {
+			plan.Current = queueSettings(current)
+		}
+	}
+
+	plan.Changes = diffQueueSettings(plan.Current, plan.Desired)
+	return plan, nil
+}
+
+// queueSettings extracts the compared settings from a queue configuration
+func queueSettings(config *clients.QoSConfig) *QueueSettings {
+	settings := &QueueSettings{
+		Name:     config.Name,
+		UpRate:   config.UpRate,
+		DownRate: config.DownRate,
+	}
+	burst := func(key string) string {
+		value, _ := config.Extra[key].(string)
+		return clients.NormalizeBurst(key, value)
+	}
+	settings.BurstLimit = burst(clients.ExtraBurstLimit)
+	settings.BurstThreshold = burst(clients.ExtraBurstThreshold)
+	settings.BurstTime = burst(clients.ExtraBurstTime)
+	return settings
+}
+
+// diffQueueSettings lists the settings of desired that differ from current.
+// A missing current queue reports every desired setting as a change.
+func diffQueueSettings(current, desired *QueueSettings) []QueueChange {
+	if current == nil {
+		current = &QueueSettings{}
+	}
+
+	changes := []QueueChange{}
+	add := func(field string, cur, want interface{}) {
+		if cur != want {
+			changes = append(changes, QueueChange{Field: field, Current: cur, Desired: want})
+		}
+	}
+	add("name", current.Name, desired.Name)
+	add("up_rate", current.UpRate, desired.UpRate)
+	add("down_rate", current.DownRate, desired.DownRate)
+	add("burst_limit", current.BurstLimit, desired.BurstLimit)
+	add("burst_threshold", current.BurstThreshold, desired.BurstThreshold)
+	add("burst_time", current.BurstTime, desired.BurstTime)
+	return changes
+}
@@ -0,0 +1,59 @@
+package qos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/qos/clients"
+	"go.uber.org/zap"
+)
+
+// ErrPPPProfileUnsupported is returned for devices whose client cannot manage PPP profiles
+var ErrPPPProfileUnsupported = errors.New("PPP profiles are not supported by this NAS vendor")
+
+// PushProfileRate sets the rate limit of the PPP profile named after a RADIUS
+// profile to the profile's rates and returns the profile as read back from the device
+func (s *NasQoSService) PushProfileRate(ctx context.Context, nas *domain.NetNas, profile *domain.RadiusProfile) (*clients.PPPProfile, error) {
+	client, err := s.pppProfileClient(ctx, nas)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fail early when the profile does not exist on the device
+	if _, err := client.GetPPPProfile(ctx, profile.Name); err != nil {
+		return nil, err
+	}
+
+	if err := client.SetPPPProfileRateLimit(ctx, profile.Name, profile.UpRate, profile.DownRate); err != nil {
+		s.dropClient(nas)
+		return nil, err
+	}
+
+	zap.L().Info("pushed profile rate to NAS",
+		zap.Int64("nas_id", nas.ID),
+		zap.String("nas_addr", nas.Ipaddr),
+		zap.String("profile", profile.Name),
+	)
+
+	return client.GetPPPProfile(ctx, profile.Name)
+}
+
+// pppProfileClient returns the NAS client if it can manage PPP profiles
+func (s *NasQoSService) pppProfileClient(ctx context.Context, nas *domain.NetNas) (clients.PPPProfileClient, error) {
+	if !nas.QoSEnabled {
+		return nil, fmt.Errorf("QoS disabled on NAS %s", nas.Ipaddr)
+	}
+
+	client, err := s.getOrCreateClient(ctx, nas)
+	if err != nil {
+		return nil, err
+	}
+
+	pppClient, ok := client.(clients.PPPProfileClient)
+	if !ok {
+		return nil, ErrPPPProfileUnsupported
+	}
+	return pppClient, nil
+}
@@ -31,11 +31,13 @@ type NasQoSRepository interface {
 	// GetByRemoteID retrieves a QoS record by remote device ID
 	GetByRemoteID(ctx context.Context, remoteID string) (*domain.NasQoS, error)
 
-	// GetPending retrieves all pending QoS records (status = 'pending')
-	GetPending(ctx context.Context, limit int) ([]*domain.NasQoS, error)
+	// GetPending retrieves pending QoS records (status = 'pending'), leaving
+	// out the records of the NAS devices in skipNas
+	GetPending(ctx context.Context, limit int, skipNas []int64) ([]*domain.NasQoS, error)
 
-	// GetFailed retrieves all failed QoS records (status = 'failed')
-	GetFailed(ctx context.Context, limit int) ([]*domain.NasQoS, error)
+	// GetFailed retrieves failed QoS records (status = 'failed') that may be
+	// retried, leaving out the records of the NAS devices in skipNas
+	GetFailed(ctx context.Context, limit int, skipNas []int64) ([]*domain.NasQoS, error)
 
 	// GetByUserAndNas retrieves QoS record for a specific user on a specific NAS
 	GetByUserAndNas(ctx context.Context, userID, nasID int64) (*domain.NasQoS, error)
@@ -48,6 +50,7 @@ type NasQoSRepository interface {
 
 	// UpdateStatus updates the status and error message of a QoS record
 	UpdateStatus(ctx context.Context, id int64, status, errorMsg string) error
+	MarkFailed(ctx context.Context, id int64, errorMsg, deviceError string) error
 
 	// IncrementRetry increments the retry counter
 	IncrementRetry(ctx context.Context, id int64) error
@@ -98,21 +101,28 @@ func (r *GormNasQoSRepository) GetByRemoteID(ctx context.Context, remoteID strin
 	return &qos, err
 }
 
-func (r *GormNasQoSRepository) GetPending(ctx context.Context, limit int) ([]*domain.NasQoS, error) {
+func (r *GormNasQoSRepository) GetPending(ctx context.Context, limit int, skipNas []int64) ([]*domain.NasQoS, error) {
 	var qos []*domain.NasQoS
-	err := r.DB.WithContext(ctx).
-		Where("status = ?", "pending").
+	query := r.DB.WithContext(ctx).Where("status = ?", "pending")
+	if len(skipNas) > 0 {
+		query = query.Where("nas_id NOT IN ?", skipNas)
+	}
+	err := query.
 		Order("created_at ASC").
 		Limit(limit).
 		Find(&qos).Error
 	return qos, err
 }
 
-func (r *GormNasQoSRepository) GetFailed(ctx context.Context, limit int) ([]*domain.NasQoS, error) {
+func (r *GormNasQoSRepository) GetFailed(ctx context.Context, limit int, skipNas []int64) ([]*domain.NasQoS, error) {
 	var qos []*domain.NasQoS
-	err := r.DB.WithContext(ctx).
+	query := r.DB.WithContext(ctx).
 		Where("status = ?", "failed").
-		Where("retry_count < 3").
+		Where("retry_count < 3")
+	if len(skipNas) > 0 {
+		query = query.Where("nas_id NOT IN ?", skipNas)
+	}
+	err := query.
 		Order("created_at ASC").
 		Limit(limit).
 		Find(&qos).Error
@@ -145,6 +155,18 @@ func (r *GormNasQoSRepository) UpdateStatus(ctx context.Context, id int64, statu
 		}).Error
 }
 
+// MarkFailed records a failed sync with its summary and the full device error
+func (r *GormNasQoSRepository) MarkFailed(ctx context.Context, id int64, errorMsg, deviceError string) error {
+	return r.DB.WithContext(ctx).
+		Model(&domain.NasQoS{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       "failed",
+			"error_msg":    errorMsg,
+			"device_error": deviceError,
+		}).Error
+}
+
 func (r *GormNasQoSRepository) IncrementRetry(ctx context.Context, id int64) error {
 	return r.DB.WithContext(ctx).
 		Model(&domain.NasQoS{}).
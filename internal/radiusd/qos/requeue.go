@@ -0,0 +1,86 @@
+package qos
+
+import (
+	"context"
+
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"gorm.io/gorm"
+)
+
+// MaxRequeueFailed bounds how many failed QoS records one bulk retry re-queues
+const MaxRequeueFailed = 5000
+
+// RequeueNas counts the failed QoS records re-queued for one NAS device
+type RequeueNas struct {
+	NasID    int64  `json:"nas_id,string"`
+	NasAddr  string `json:"nas_addr"`
+	Requeued int64  `json:"requeued"`
+}
+
+// RequeueResult reports a bulk retry of failed QoS records
+type RequeueResult struct {
+	Requeued  int64        `json:"requeued"`
+	Remaining int64        `json:"remaining"` // Failed records left beyond the limit
+	Nas       []RequeueNas `json:"nas"`
+}
+
+// RequeueFailed resets the retry state of up to limit failed QoS records
+// across all NAS devices, oldest first, and sets them back to pending so
+// the next sync pass picks them up
+func (s *NasQoSService) RequeueFailed(ctx context.Context, limit int) (*RequeueResult, error) {
+	if limit <= 0 || limit > MaxRequeueFailed {
+		limit = MaxRequeueFailed
+	}
+
+	result := &RequeueResult{Nas: []RequeueNas{}}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var failed []domain.NasQoS
+		if err := tx.Select("id", "nas_id", "nas_addr").
+			Where("status = ?", "failed").
+			Order("updated_at ASC").
+			Limit(limit).
+			Find(&failed).Error; err != nil {
+			return err
+		}
+		if len(failed) == 0 {
+			return nil
+		}
+
+		ids := make([]int64, 0, len(failed))
+		index := make(map[int64]int)
+		for _, qos := range failed {
+			ids = append(ids, qos.ID)
+			i, found := index[qos.NasID]
+			if !found {
+				i = len(result.Nas)
+				index[qos.NasID] = i
+				result.Nas = append(result.Nas, RequeueNas{NasID: qos.NasID, NasAddr: qos.NasAddr})
+			}
+			result.Nas[i].Requeued++
+		}
+
+		res := tx.Model(&domain.NasQoS{}).
+			Where("id IN ? AND status = ?", ids, "failed").
+			Updates(map[string]interface{}{
+				"status":       "pending",
+				"retry_count":  0,
+				"error_msg":    "",
+				"device_error": "",
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Requeued = res.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Model(&domain.NasQoS{}).
+		Where("status = ?", "failed").
+		Count(&result.Remaining).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -2,8 +2,12 @@ package qos
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/talkincode/toughradius/v9/internal/domain"
@@ -14,14 +18,29 @@ import (
 
 // NasQoSService handles QoS synchronization with NAS devices
 type NasQoSService struct {
-	db          *gorm.DB
-	qosRepo     NasQoSRepository
-	logRepo     NasQoSLogRepository
-	nasRepo     NasRepository
-	userRepo    UserRepository
-	clientPool  map[string]clients.QoSClient // Cache of active client connections
-	syncTicker  *time.Ticker
-	stopChan    chan struct{}
+	db         *gorm.DB
+	qosRepo    NasQoSRepository
+	logRepo    NasQoSLogRepository
+	nasRepo    NasRepository
+	userRepo   UserRepository
+	clientPool map[string]clients.QoSClient // Cache of active client connections
+	poolMu     sync.Mutex
+	dialing    map[string]*sync.Mutex // Serializes dials per API host
+	newClient  func(ctx context.Context, nas *domain.NetNas) (clients.QoSClient, error)
+	liveCache  map[int64]*LiveQueues // Recently fetched device queue lists by NAS ID
+	liveMu     sync.Mutex
+	syncTicker *time.Ticker
+	stopChan   chan struct{}
+	syncPaused func() bool // Global maintenance pause of device syncs, set before Start
+
+	backlogMu     sync.Mutex
+	backlogConfig BacklogAlertConfig
+	backlogAlert  func(alert BacklogAlert)
+	backlogNas    map[int64]int64         // Last published backlog by NAS ID
+	backlogState  map[int64]*backlogState // NAS devices currently above the alert threshold
+	now           func() time.Time
+
+	throttle syncThrottle
 }
 
 // NewNasQoSService creates a new QoS sync service
@@ -39,7 +58,17 @@ func NewNasQoSService(
 		nasRepo:    nasRepo,
 		userRepo:   userRepo,
 		clientPool: make(map[string]clients.QoSClient),
+		dialing:    make(map[string]*sync.Mutex),
+		newClient:  newVendorClient,
+		liveCache:  make(map[int64]*LiveQueues),
 		stopChan:   make(chan struct{}),
+
+		backlogAlert: logBacklogAlert,
+		backlogNas:   make(map[int64]int64),
+		backlogState: make(map[int64]*backlogState),
+		now:          time.Now,
+
+		throttle: newSyncThrottle(),
 	}
 }
 
@@ -50,6 +79,7 @@ func (s *NasQoSService) Start(ctx context.Context, interval time.Duration) {
 		interval = 1 * time.Minute // Default to 1 minute
 	}
 
+	s.throttle.tickBudget = interval
 	s.syncTicker = time.NewTicker(interval)
 	go s.syncLoop(ctx)
 
@@ -61,7 +91,49 @@ func (s *NasQoSService) Start(ctx context.Context, interval time.Duration) {
 // SyncQueue is a public method to manually sync a single QoS queue
 // This is useful for testing and manual triggering of QoS sync
 func (s *NasQoSService) SyncQueue(ctx context.Context, qos *domain.NasQoS) {
-	s.syncQueue(ctx, qos)
+	_ = s.syncQueue(ctx, qos) //nolint:errcheck // the error is recorded on the QoS record
+}
+
+// SyncQueueNow resets the retry state of a single QoS record and syncs it
+// immediately, returning the record as stored after the attempt
+func (s *NasQoSService) SyncQueueNow(ctx context.Context, id int64) (*domain.NasQoS, error) {
+	qos, err := s.qosRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	qos.RetryCount = 0
+	qos.ErrorMsg = ""
+	qos.DeviceError = ""
+	if err := s.qosRepo.Update(ctx, qos); err != nil {
+		return nil, err
+	}
+
+	_ = s.syncQueue(ctx, qos) //nolint:errcheck // the error is recorded on the QoS record
+
+	return s.qosRepo.GetByID(ctx, id)
+}
+
+// SetClientFactory replaces how vendor clients are created, mainly for tests
+func (s *NasQoSService) SetClientFactory(factory func(ctx context.Context, nas *domain.NetNas) (clients.QoSClient, error)) {
+	s.poolMu.Lock()
+	defer s.poolMu.Unlock()
+	s.newClient = factory
+	s.clientPool = make(map[string]clients.QoSClient)
+}
+
+// SetSyncPaused sets where the global QoS sync pause is read from
+func (s *NasQoSService) SetSyncPaused(paused func() bool) {
+	s.syncPaused = paused
+}
+
+// ErrSyncPaused is returned for device changes requested while QoS sync is paused
+var ErrSyncPaused = errors.New("QoS sync is paused")
+
+// SyncPaused reports whether QoS syncs to devices are paused globally.
+// The per-NAS QoSEnabled flags are left untouched while paused.
+func (s *NasQoSService) SyncPaused() bool {
+	return s.syncPaused != nil && s.syncPaused()
 }
 
 // Stop gracefully stops the QoS sync service
@@ -71,6 +143,8 @@ func (s *NasQoSService) Stop() {
 	}
 
 	// Close all cached client connections
+	s.poolMu.Lock()
+	defer s.poolMu.Unlock()
 	for addr, client := range s.clientPool {
 		if err := client.Close(); err != nil {
 			zap.L().Warn("error closing client connection",
@@ -98,42 +172,13 @@ func (s *NasQoSService) syncLoop(ctx context.Context) {
 	}
 }
 
-// syncPendingQueues processes all pending QoS records
-func (s *NasQoSService) syncPendingQueues(ctx context.Context) {
-	pending, err := s.qosRepo.GetPending(ctx, 100) // Process max 100 at a time
-	if err != nil {
-		zap.L().Error("failed to get pending queues", zap.Error(err))
-		return
-	}
-
-	if len(pending) == 0 {
-		zap.L().Debug("no pending QoS queues to process")
-		return
-	}
-
-	zap.L().Info("🔄 processing pending QoS queues", zap.Int("count", len(pending)))
-
-	for _, qos := range pending {
-		s.syncQueue(ctx, qos)
-	}
-
-	// Also process failed queues (with retry logic)
-	failed, err := s.qosRepo.GetFailed(ctx, 50)
-	if err == nil && len(failed) > 0 {
-		zap.L().Debug("retrying failed queues", zap.Int("count", len(failed)))
-		for _, qos := range failed {
-			s.syncQueue(ctx, qos)
-		}
-	}
-}
-
-// syncQueue syncs a single QoS record to its NAS device
-func (s *NasQoSService) syncQueue(ctx context.Context, qos *domain.NasQoS) {
+// syncQueue syncs a single QoS record to its NAS device, returning the
+// error recorded on the record when the sync failed
+func (s *NasQoSService) syncQueue(ctx context.Context, qos *domain.NasQoS) error {
 	// Get NAS device info
 	nas := &domain.NetNas{}
 	if err := s.db.First(nas, qos.NasID).Error; err != nil {
-		s.updateQoSError(ctx, qos, fmt.Sprintf("NAS not found: %v", err))
-		return
+		return s.failQueue(ctx, qos, fmt.Sprintf("NAS not found: %v", err))
 	}
 
 	// Skip if QoS not enabled on this NAS
@@ -142,18 +187,54 @@ func (s *NasQoSService) syncQueue(ctx context.Context, qos *domain.NasQoS) {
 			zap.Int64("nas_id", nas.ID),
 			zap.String("nas_addr", nas.Ipaddr),
 		)
-		s.updateQoSError(ctx, qos, "QoS disabled on NAS")
-		return
+		return s.failQueue(ctx, qos, "QoS disabled on NAS")
+	}
+
+	if qos.RemoteConfig != "" && !json.Valid([]byte(qos.RemoteConfig)) {
+		return s.failQueue(ctx, qos, "invalid remote config")
+	}
+
+	// Device already holds the desired rates, nothing to push
+	if qos.RemoteID != "" && !qosConfigChanged(qos) {
+		s.markSynced(ctx, qos, nas, "unchanged")
+		return nil
 	}
 
 	// Get or create client for this NAS
-	client, err := s.getOrCreateClient(nas)
+	client, err := s.getOrCreateClient(ctx, nas)
 	if err != nil {
-		s.updateQoSError(ctx, qos, fmt.Sprintf("failed to create client: %v", err))
-		return
+		errMsg := fmt.Sprintf("failed to create client: %v", err)
+		if errors.Is(err, errUnsupportedVendor) {
+			return s.failQueue(ctx, qos, errMsg)
+		}
+		s.updateQoSError(ctx, qos, errMsg, "")
+		return &deviceError{msg: errMsg}
+	}
+
+	config := queueConfig(qos)
+
+	// Create queue on NAS if not already synced, otherwise push the changed rates
+	if qos.RemoteID == "" {
+		remoteID, err := client.CreateQueue(ctx, config)
+		if err != nil {
+			s.incrementRetry(ctx, qos)
+			return s.failQueueOnDevice(ctx, qos, "create", err)
+		}
+
+		qos.RemoteID = remoteID
+	} else {
+		if err := client.UpdateQueue(ctx, qos.RemoteID, config); err != nil {
+			s.incrementRetry(ctx, qos)
+			return s.failQueueOnDevice(ctx, qos, "update", err)
+		}
 	}
 
-	// Build QoS config
+	s.markSynced(ctx, qos, nas, "synced")
+	return nil
+}
+
+// queueConfig builds the queue configuration a QoS record should have on its device
+func queueConfig(qos *domain.NasQoS) *clients.QoSConfig {
 	config := &clients.QoSConfig{
 		Name:     qos.QoSName,
 		UpRate:   qos.UpRate,
@@ -165,24 +246,40 @@ func (s *NasQoSService) syncQueue(ctx context.Context, qos *domain.NasQoS) {
 	if qos.RemoteConfig != "" {
 		json.Unmarshal([]byte(qos.RemoteConfig), &config.Extra)
 	}
+	return config
+}
 
-	// Create queue on NAS if not already synced
-	if qos.RemoteID == "" {
-		remoteID, err := client.CreateQueue(ctx, config)
-		if err != nil {
-			s.updateQoSError(ctx, qos, fmt.Sprintf("create failed: %v", err))
-			s.incrementRetry(ctx, qos)
-			return
-		}
+// qosConfigChanged reports whether the desired queue config, including the
+// burst parameters of RemoteConfig, differs from the config pushed to the
+// device at the last successful sync. Records synced before the hash was
+// kept count as changed.
+func qosConfigChanged(qos *domain.NasQoS) bool {
+	return queueConfigHash(queueConfig(qos)) != qos.SyncedConfigHash
+}
 
-		qos.RemoteID = remoteID
-	}
+// queueConfigHash fingerprints a queue config: its name, rates and Extra
+func queueConfigHash(config *clients.QoSConfig) string {
+	// Map keys are marshalled in sorted order, so equal configs hash equal
+	data, _ := json.Marshal(struct { //nolint:errcheck // Extra holds decoded JSON values only
+		Name     string                 `json:"name"`
+		UpRate   int                    `json:"up_rate"`
+		DownRate int                    `json:"down_rate"`
+		Extra    map[string]interface{} `json:"extra"`
+	}{config.Name, config.UpRate, config.DownRate, config.Extra})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
 
-	// Update status to synced
+// markSynced records a successful sync and remembers the rates now on the device
+func (s *NasQoSService) markSynced(ctx context.Context, qos *domain.NasQoS, nas *domain.NetNas, action string) {
 	qos.Status = "synced"
 	now := time.Now()
 	qos.SyncedAt = &now
+	qos.SyncedUpRate = qos.UpRate
+	qos.SyncedDownRate = qos.DownRate
+	qos.SyncedConfigHash = queueConfigHash(queueConfig(qos))
 	qos.ErrorMsg = ""
+	qos.DeviceError = ""
 	qos.RetryCount = 0
 
 	if err := s.qosRepo.Update(ctx, qos); err != nil {
@@ -194,7 +291,7 @@ func (s *NasQoSService) syncQueue(ctx context.Context, qos *domain.NasQoS) {
 	}
 
 	// Log the sync
-	s.logSync(ctx, qos, "synced", "success", "", nil, nil)
+	s.logSync(ctx, qos, action, "success", "", nil, nil)
 
 	zap.L().Info("queue synced successfully",
 		zap.String("queue_id", qos.RemoteID),
@@ -203,39 +300,180 @@ func (s *NasQoSService) syncQueue(ctx context.Context, qos *domain.NasQoS) {
 	)
 }
 
-// getOrCreateClient gets or creates a QoS client for a NAS device
-func (s *NasQoSService) getOrCreateClient(nas *domain.NetNas) (clients.QoSClient, error) {
-	// Check if client already cached
-	if client, ok := s.clientPool[nas.Ipaddr]; ok {
+// deviceHost is the management API host of a NAS, which keys both the
+// client pool and the RouterOS session limiter
+func deviceHost(nas *domain.NetNas) string {
+	if nas.APIHost != "" {
+		return nas.APIHost
+	}
+	return nas.Ipaddr
+}
+
+// getOrCreateClient gets or creates a QoS client for a NAS device. NAS
+// records sharing an API host share one client. The dial runs outside
+// poolMu so a slow or saturated device does not block other devices.
+func (s *NasQoSService) getOrCreateClient(ctx context.Context, nas *domain.NetNas) (clients.QoSClient, error) {
+	host := deviceHost(nas)
+
+	s.poolMu.Lock()
+	if client, ok := s.clientPool[host]; ok {
+		s.poolMu.Unlock()
+		return client, nil
+	}
+	dialMu, ok := s.dialing[host]
+	if !ok {
+		dialMu = &sync.Mutex{}
+		s.dialing[host] = dialMu
+	}
+	s.poolMu.Unlock()
+
+	// One dial per host at a time, later callers reuse its client
+	dialMu.Lock()
+	defer dialMu.Unlock()
+
+	s.poolMu.Lock()
+	client, ok := s.clientPool[host]
+	s.poolMu.Unlock()
+	if ok {
 		return client, nil
 	}
 
-	// Create new client based on vendor and method
+	client, err := s.newClient(ctx, nas)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the client
+	s.poolMu.Lock()
+	s.clientPool[host] = client
+	s.poolMu.Unlock()
+
+	return client, nil
+}
+
+// dropClient closes and forgets the cached client of a NAS so the next call reconnects
+func (s *NasQoSService) dropClient(nas *domain.NetNas) {
+	s.poolMu.Lock()
+	defer s.poolMu.Unlock()
+
+	host := deviceHost(nas)
+	if client, ok := s.clientPool[host]; ok {
+		_ = client.Close() //nolint:errcheck
+		delete(s.clientPool, host)
+	}
+}
+
+// errUnsupportedVendor is returned for NAS vendors without a QoS client
+var errUnsupportedVendor = errors.New("unsupported vendor")
+
+// newVendorClient creates a QoS client based on the NAS vendor and method
+func newVendorClient(ctx context.Context, nas *domain.NetNas) (clients.QoSClient, error) {
 	var client clients.QoSClient
 	var err error
 
 	switch nas.VendorCode {
 	case "14988": // Mikrotik
 		client, err = clients.NewMikrotikClient(
-			nas.APIHost,
+			ctx,
+			deviceHost(nas),
 			nas.APIUsername,
 			nas.APIPassword,
 			nas.APIPort,
 		)
 	default:
-		return nil, fmt.Errorf("unsupported vendor: %s", nas.VendorCode)
+		return nil, fmt.Errorf("%w: %s", errUnsupportedVendor, nas.VendorCode)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	// Cache the client
-	s.clientPool[nas.Ipaddr] = client
-
 	return client, nil
 }
 
+// LiveQueueCacheTTL is how long a device queue list is served from cache
+const LiveQueueCacheTTL = 10 * time.Second
+
+// LiveQueues is the queue list of a NAS, read from the device or, when the
+// device is unreachable, from the last synced NasQoS records
+type LiveQueues struct {
+	NasID     int64                `json:"nas_id,string"`
+	Source    string               `json:"source"` // "live" or "snapshot"
+	Stale     bool                 `json:"stale"`
+	FetchedAt time.Time            `json:"fetched_at"`
+	Error     string               `json:"error,omitempty"`
+	Queues    []clients.QueueEntry `json:"queues"`
+}
+
+// GetLiveQueues returns the queues currently on a NAS device. Results are
+// cached for LiveQueueCacheTTL to avoid hammering the device on refreshes.
+func (s *NasQoSService) GetLiveQueues(ctx context.Context, nas *domain.NetNas) *LiveQueues {
+	s.liveMu.Lock()
+	cached, ok := s.liveCache[nas.ID]
+	s.liveMu.Unlock()
+	if ok && time.Since(cached.FetchedAt) < LiveQueueCacheTTL {
+		return cached
+	}
+
+	client, err := s.getOrCreateClient(ctx, nas)
+	if err == nil {
+		var entries []clients.QueueEntry
+		entries, err = client.ListQueues(ctx)
+		if err == nil {
+			result := &LiveQueues{
+				NasID:     nas.ID,
+				Source:    "live",
+				FetchedAt: time.Now(),
+				Queues:    entries,
+			}
+			s.liveMu.Lock()
+			s.liveCache[nas.ID] = result
+			s.liveMu.Unlock()
+			return result
+		}
+		s.dropClient(nas)
+	}
+
+	zap.L().Warn("failed to read live queues, using snapshot",
+		zap.Int64("nas_id", nas.ID),
+		zap.String("nas_addr", nas.Ipaddr),
+		zap.Error(err),
+	)
+
+	result := s.queueSnapshot(nas.ID)
+	result.Error = err.Error()
+	return result
+}
+
+// queueSnapshot builds a queue list from the locally stored QoS records
+func (s *NasQoSService) queueSnapshot(nasID int64) *LiveQueues {
+	result := &LiveQueues{
+		NasID:  nasID,
+		Source: "snapshot",
+		Stale:  true,
+		Queues: make([]clients.QueueEntry, 0),
+	}
+
+	var records []domain.NasQoS
+	if err := s.db.Where("nas_id = ? AND remote_id <> ''", nasID).Order("id ASC").Find(&records).Error; err != nil {
+		zap.L().Error("failed to load queue snapshot", zap.Int64("nas_id", nasID), zap.Error(err))
+		return result
+	}
+
+	for _, record := range records {
+		result.Queues = append(result.Queues, clients.QueueEntry{
+			ID:       record.RemoteID,
+			Name:     record.QoSName,
+			UpRate:   record.SyncedUpRate,
+			DownRate: record.SyncedDownRate,
+		})
+		if record.SyncedAt != nil && record.SyncedAt.After(result.FetchedAt) {
+			result.FetchedAt = *record.SyncedAt
+		}
+	}
+	return result
+}
+
 // CreateUserQueue creates a QoS queue for a user on a specific NAS
 func (s *NasQoSService) CreateUserQueue(ctx context.Context, userID, nasID int64) error {
 	// Get user
@@ -273,6 +511,10 @@ func (s *NasQoSService) CreateUserQueue(ctx context.Context, userID, nasID int64
 
 // DeleteUserQueue deletes a QoS queue for a user
 func (s *NasQoSService) DeleteUserQueue(ctx context.Context, userID, nasID int64) error {
+	if s.SyncPaused() {
+		return ErrSyncPaused
+	}
+
 	qos, err := s.qosRepo.GetByUserAndNas(ctx, userID, nasID)
 	if err != nil {
 		return err
@@ -289,7 +531,7 @@ func (s *NasQoSService) DeleteUserQueue(ctx context.Context, userID, nasID int64
 			return err
 		}
 
-		client, err := s.getOrCreateClient(nas)
+		client, err := s.getOrCreateClient(ctx, nas)
 		if err != nil {
 			return err
 		}
@@ -309,12 +551,26 @@ func (s *NasQoSService) DeleteUserQueue(ctx context.Context, userID, nasID int64
 
 // Helper methods
 
-func (s *NasQoSService) updateQoSError(ctx context.Context, qos *domain.NasQoS, errMsg string) {
-	if err := s.qosRepo.UpdateStatus(ctx, qos.ID, "failed", errMsg); err != nil {
+func (s *NasQoSService) updateQoSError(ctx context.Context, qos *domain.NasQoS, errMsg, deviceErr string) {
+	if err := s.qosRepo.MarkFailed(ctx, qos.ID, errMsg, deviceErr); err != nil {
 		zap.L().Error("failed to update error status", zap.Error(err))
 	}
 }
 
+// failQueue records a sync failure on qos and returns it as an error
+func (s *NasQoSService) failQueue(ctx context.Context, qos *domain.NasQoS, errMsg string) error {
+	s.updateQoSError(ctx, qos, errMsg, "")
+	return errors.New(errMsg)
+}
+
+// failQueueOnDevice records a sync failure caused by a device operation,
+// keeping the full device error next to the summary
+func (s *NasQoSService) failQueueOnDevice(ctx context.Context, qos *domain.NasQoS, action string, err error) error {
+	errMsg := fmt.Sprintf("%s failed: %v", action, err)
+	s.updateQoSError(ctx, qos, errMsg, clients.TrapMessage(err))
+	return &deviceError{msg: errMsg}
+}
+
 func (s *NasQoSService) incrementRetry(ctx context.Context, qos *domain.NasQoS) {
 	if err := s.qosRepo.IncrementRetry(ctx, qos.ID); err != nil {
 		zap.L().Error("failed to increment retry", zap.Error(err))
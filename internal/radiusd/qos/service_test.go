@@ -0,0 +1,320 @@
+package qos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/domain"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/qos/clients"
+	"github.com/talkincode/toughradius/v9/pkg/metrics"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// mockQoSClient records queue operations instead of talking to a device
+type mockQoSClient struct {
+	mu      sync.Mutex
+	creates []*clients.QoSConfig
+	updates map[string]*clients.QoSConfig
+	deletes []string
+	nextID  string
+	err     error
+	delay   time.Duration // Simulated device latency of CreateQueue
+	tries   int           // CreateQueue calls, including failed ones
+}
+
+func newMockQoSClient() *mockQoSClient {
+	return &mockQoSClient{
+		updates: make(map[string]*clients.QoSConfig),
+		nextID:  "*1",
+	}
+}
+
+func (m *mockQoSClient) CreateQueue(_ context.Context, config *clients.QoSConfig) (string, error) {
+	time.Sleep(m.delay)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tries++
+	if m.err != nil {
+		return "", m.err
+	}
+	m.creates = append(m.creates, config)
+	return m.nextID, nil
+}
+
+func (m *mockQoSClient) DeleteQueue(_ context.Context, remoteID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.deletes = append(m.deletes, remoteID)
+	return nil
+}
+
+func (m *mockQoSClient) UpdateQueue(_ context.Context, remoteID string, config *clients.QoSConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.updates[remoteID] = config
+	return nil
+}
+
+func (m *mockQoSClient) GetQueue(_ context.Context, remoteID string) (*clients.QoSConfig, error) {
+	return nil, m.err
+}
+
+func (m *mockQoSClient) ListQueues(_ context.Context) ([]clients.QueueEntry, error) {
+	return nil, m.err
+}
+
+func (m *mockQoSClient) Close() error {
+	return nil
+}
+
+// setupTestService creates a QoS service backed by an in-memory database and a mock client
+func setupTestService(t *testing.T) (*NasQoSService, *gorm.DB, *mockQoSClient) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	// Every connection to :memory: opens a separate database, so the sync
+	// workers must share a single one
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&domain.NetNas{}, &domain.NasQoS{}, &domain.NasQoSLog{}))
+
+	client := newMockQoSClient()
+	svc := NewNasQoSService(db, &GormNasQoSRepository{DB: db}, &GormNasQoSLogRepository{DB: db}, nil, nil)
+	svc.newClient = func(_ context.Context, nas *domain.NetNas) (clients.QoSClient, error) {
+		return client, nil
+	}
+	return svc, db, client
+}
+
+// createTestNas creates a QoS enabled Mikrotik NAS
+func createTestNas(t *testing.T, db *gorm.DB) *domain.NetNas {
+	nas := &domain.NetNas{
+		ID:         1,
+		Name:       "mikrotik-test",
+		Ipaddr:     "10.0.0.1",
+		VendorCode: "14988",
+		Status:     "enabled",
+		QoSEnabled: true,
+	}
+	require.NoError(t, db.Create(nas).Error)
+	return nas
+}
+
+func TestSyncQueue_CreatesNewQueue(t *testing.T) {
+	svc, db, client := setupTestService(t)
+	nas := createTestNas(t, db)
+
+	qos := &domain.NasQoS{ID: 1, UserID: 10, NasID: nas.ID, QoSName: "user_10", UpRate: 1024, DownRate: 2048, Status: "pending"}
+	require.NoError(t, db.Create(qos).Error)
+
+	svc.SyncQueue(context.Background(), qos)
+
+	assert.Len(t, client.creates, 1)
+	assert.Empty(t, client.updates)
+
+	var stored domain.NasQoS
+	require.NoError(t, db.First(&stored, qos.ID).Error)
+	assert.Equal(t, "synced", stored.Status)
+	assert.Equal(t, "*1", stored.RemoteID)
+	assert.Equal(t, 1024, stored.SyncedUpRate)
+	assert.Equal(t, 2048, stored.SyncedDownRate)
+}
+
+func TestSyncQueue_UnchangedSkipsUpdate(t *testing.T) {
+	svc, db, client := setupTestService(t)
+	nas := createTestNas(t, db)
+
+	qos := &domain.NasQoS{
+		ID: 1, UserID: 10, NasID: nas.ID, QoSName: "user_10",
+		UpRate: 1024, DownRate: 2048, SyncedUpRate: 1024, SyncedDownRate: 2048,
+		RemoteID: "*5", Status: "pending",
+	}
+	qos.SyncedConfigHash = queueConfigHash(queueConfig(qos))
+	require.NoError(t, db.Create(qos).Error)
+
+	svc.SyncQueue(context.Background(), qos)
+
+	assert.Empty(t, client.creates)
+	assert.Empty(t, client.updates)
+
+	var stored domain.NasQoS
+	require.NoError(t, db.First(&stored, qos.ID).Error)
+	assert.Equal(t, "synced", stored.Status)
+}
+
+func TestSyncQueue_ChangedRatesUpdateQueue(t *testing.T) {
+	svc, db, client := setupTestService(t)
+	nas := createTestNas(t, db)
+
+	qos := &domain.NasQoS{
+		ID: 1, UserID: 10, NasID: nas.ID, QoSName: "user_10",
+		UpRate: 4096, DownRate: 8192, SyncedUpRate: 1024, SyncedDownRate: 2048,
+		RemoteID: "*5", Status: "pending",
+	}
+	require.NoError(t, db.Create(qos).Error)
+
+	svc.SyncQueue(context.Background(), qos)
+
+	assert.Empty(t, client.creates)
+	require.Contains(t, client.updates, "*5")
+	assert.Equal(t, 4096, client.updates["*5"].UpRate)
+	assert.Equal(t, 8192, client.updates["*5"].DownRate)
+
+	var stored domain.NasQoS
+	require.NoError(t, db.First(&stored, qos.ID).Error)
+	assert.Equal(t, "synced", stored.Status)
+	assert.Equal(t, 4096, stored.SyncedUpRate)
+	assert.Equal(t, 8192, stored.SyncedDownRate)
+}
+
+func TestSyncQueue_ChangedBurstUpdatesQueue(t *testing.T) {
+	svc, db, client := setupTestService(t)
+	nas := createTestNas(t, db)
+
+	qos := &domain.NasQoS{
+		ID: 1, UserID: 10, NasID: nas.ID, QoSName: "user_10",
+		UpRate: 1024, DownRate: 2048, SyncedUpRate: 1024, SyncedDownRate: 2048,
+		RemoteConfig: `{"burst_limit":"2048k/4096k"}`,
+		RemoteID:     "*5", Status: "pending",
+	}
+	qos.SyncedConfigHash = queueConfigHash(queueConfig(qos))
+
+	// Only the burst changes, the rates stay the same
+	qos.RemoteConfig = `{"burst_limit":"4096k/8192k"}`
+	require.NoError(t, db.Create(qos).Error)
+	assert.True(t, qosConfigChanged(qos))
+
+	svc.SyncQueue(context.Background(), qos)
+
+	require.Contains(t, client.updates, "*5")
+	assert.Equal(t, "4096k/8192k", client.updates["*5"].Extra["burst_limit"])
+
+	var stored domain.NasQoS
+	require.NoError(t, db.First(&stored, qos.ID).Error)
+	assert.Equal(t, "synced", stored.Status)
+	assert.False(t, qosConfigChanged(&stored), "the pushed config is remembered")
+}
+
+func TestSyncQueue_StoresDeviceTrapMessage(t *testing.T) {
+	svc, db, client := setupTestService(t)
+	nas := createTestNas(t, db)
+
+	qos := &domain.NasQoS{ID: 1, UserID: 10, NasID: nas.ID, QoSName: "user_10", UpRate: 1024, DownRate: 2048, Status: "pending"}
+	require.NoError(t, db.Create(qos).Error)
+
+	client.err = fmt.Errorf("create queue error: %w", &clients.TrapError{
+		Category: "general failure",
+		Message:  "failure: already have queue with this name",
+	})
+	svc.SyncQueue(context.Background(), qos)
+
+	var stored domain.NasQoS
+	require.NoError(t, db.First(&stored, qos.ID).Error)
+	assert.Equal(t, "failed", stored.Status)
+	assert.Contains(t, stored.ErrorMsg, "create failed")
+	assert.Equal(t, "general failure: failure: already have queue with this name", stored.DeviceError)
+
+	// A successful sync clears the device error
+	client.err = nil
+	_, err := svc.SyncQueueNow(context.Background(), qos.ID)
+	require.NoError(t, err)
+	require.NoError(t, db.First(&stored, qos.ID).Error)
+	assert.Equal(t, "synced", stored.Status)
+	assert.Empty(t, stored.DeviceError)
+}
+
+func TestDeleteUserQueue_PausedKeepsQueue(t *testing.T) {
+	svc, db, client := setupTestService(t)
+	nas := createTestNas(t, db)
+
+	qos := &domain.NasQoS{ID: 1, UserID: 10, NasID: nas.ID, QoSName: "user_10", RemoteID: "*5", Status: "synced"}
+	require.NoError(t, db.Create(qos).Error)
+
+	paused := true
+	svc.SetSyncPaused(func() bool { return paused })
+	assert.ErrorIs(t, svc.DeleteUserQueue(context.Background(), 10, nas.ID), ErrSyncPaused)
+	assert.Empty(t, client.deletes)
+	require.NoError(t, db.First(&domain.NasQoS{}, qos.ID).Error)
+
+	paused = false
+	require.NoError(t, svc.DeleteUserQueue(context.Background(), 10, nas.ID))
+	assert.Equal(t, []string{"*5"}, client.deletes)
+}
+
+func TestCheckBacklog_AlertsOnceWhenSustained(t *testing.T) {
+	require.NoError(t, metrics.InitMetrics(""))
+	svc, db, client := setupTestService(t)
+	nas := createTestNas(t, db)
+	client.err = errors.New("connection refused")
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	svc.SetBacklogAlertConfig(func() (int64, time.Duration) {
+		return 3, 10 * time.Minute
+	})
+	var alerts []BacklogAlert
+	svc.SetBacklogAlertHandler(func(alert BacklogAlert) {
+		alerts = append(alerts, alert)
+	})
+
+	nextID := int64(1)
+	grow := func(n int) {
+		for i := 0; i < n; i++ {
+			require.NoError(t, db.Create(&domain.NasQoS{
+				ID: nextID, UserID: nextID, NasID: nas.ID, QoSName: fmt.Sprintf("user_%d", nextID),
+				UpRate: 1024, DownRate: 1024, Status: "pending",
+			}).Error)
+			nextID++
+		}
+	}
+	gauge := func() int64 {
+		return metrics.GetStore().GetGaugeValue(fmt.Sprintf("%s%d", MetricsQoSBacklogNasPrefix, nas.ID))
+	}
+
+	// Backlog below the threshold
+	grow(2)
+	svc.syncPendingQueues(context.Background())
+	assert.Equal(t, int64(2), gauge())
+	assert.Equal(t, int64(2), metrics.GetStore().GetGaugeValue(MetricsQoSBacklog))
+	assert.Empty(t, alerts)
+
+	// Threshold exceeded but not yet for long enough
+	grow(3)
+	now = now.Add(time.Minute)
+	svc.syncPendingQueues(context.Background())
+	assert.Equal(t, int64(5), gauge())
+	assert.Empty(t, alerts)
+
+	// Sustained growth alerts exactly once
+	for i := 0; i < 4; i++ {
+		grow(2)
+		now = now.Add(5 * time.Minute)
+		svc.syncPendingQueues(context.Background())
+	}
+	require.Len(t, alerts, 1)
+	assert.Equal(t, nas.ID, alerts[0].NasID)
+	assert.Equal(t, int64(3), alerts[0].Threshold)
+	assert.GreaterOrEqual(t, alerts[0].Backlog, int64(9))
+
+	// Draining the backlog resets the gauge and re-arms the alert
+	require.NoError(t, db.Where("1 = 1").Delete(&domain.NasQoS{}).Error)
+	svc.syncPendingQueues(context.Background())
+	assert.Equal(t, int64(0), gauge())
+	assert.Empty(t, svc.backlogState)
+}
@@ -33,6 +33,9 @@ func (m *mockAppContext) ProfileCache() *app.ProfileCache                    { r
 func (m *mockAppContext) MigrateDB(track bool) error                         { return nil }
 func (m *mockAppContext) InitDb()                                            {}
 func (m *mockAppContext) DropAll()                                           {}
+func (m *mockAppContext) GetQoSService() interface{}                         { return nil }
+func (m *mockAppContext) EnsureDefaults() *app.DefaultsReport                { return &app.DefaultsReport{} }
+func (m *mockAppContext) SelfCheck() []app.CheckResult                       { return nil }
 
 type testEnhancer struct {
 	name  string
@@ -47,6 +47,9 @@ func (r *GormSessionRepository) Update(ctx context.Context, session *domain.Radi
 		"acct_session_time":   session.AcctSessionTime,
 		"last_update":         time.Now(),
 	}
+	if session.AcctInterimInterval > 0 {
+		param["acct_interim_interval"] = session.AcctInterimInterval
+	}
 	return r.db.WithContext(ctx).
 		Model(&domain.RadiusOnline{}).
 		Where("acct_session_id = ?", session.AcctSessionId).
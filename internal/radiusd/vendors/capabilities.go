@@ -0,0 +1,38 @@
+package vendors
+
+// Capabilities describes which device management features a vendor supports
+type Capabilities struct {
+	QoS          bool `json:"qos"`            // Per-user queues can be pushed to the device
+	RateLimitCoA bool `json:"rate_limit_coa"` // Session rates can be changed with CoA
+
+	// FetchServices means the queue list can be read back from the device
+	// with GET /network/nas/:id/queues/live (ListLiveQueues)
+	FetchServices bool `json:"fetch_services"`
+}
+
+// builtinCapabilities lists the features implemented for each vendor code
+var builtinCapabilities = map[string]Capabilities{
+	CodeMikrotik: {QoS: true, FetchServices: true, RateLimitCoA: true},
+	CodeHuawei:   {RateLimitCoA: true},
+	CodeH3C:      {RateLimitCoA: true},
+	CodeZTE:      {RateLimitCoA: true},
+	CodeIkuai:    {RateLimitCoA: true},
+}
+
+// Capabilities returns the feature flags of a vendor. A capability set
+// registered with the vendor takes precedence over the built-in one;
+// unknown vendors support nothing.
+func (r *VendorRegistry) Capabilities(code string) Capabilities {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if info, ok := r.vendors[code]; ok && info.Capabilities != nil {
+		return *info.Capabilities
+	}
+	return builtinCapabilities[code]
+}
+
+// GetCapabilities returns the feature flags of a vendor from the global registry
+func GetCapabilities(code string) Capabilities {
+	return globalRegistry.Capabilities(code)
+}
@@ -0,0 +1,92 @@
+package vendors
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors/cisco"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors/huawei"
+	"github.com/talkincode/toughradius/v9/internal/radiusd/vendors/mikrotik"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2865"
+)
+
+func TestSetRateLimit(t *testing.T) {
+	t.Run("Mikrotik", func(t *testing.T) {
+		p := radius.New(radius.CodeCoARequest, []byte("secret"))
+		assert.True(t, SetRateLimit(p, CodeMikrotik, 1024, 2048))
+		assert.Equal(t, "1024k/2048k", mikrotik.MikrotikRateLimit_GetString(p))
+	})
+
+	t.Run("Huawei", func(t *testing.T) {
+		p := radius.New(radius.CodeCoARequest, []byte("secret"))
+		assert.True(t, SetRateLimit(p, CodeHuawei, 1024, 2048))
+		assert.Equal(t, huawei.HuaweiInputAverageRate(1024*1024), huawei.HuaweiInputAverageRate_Get(p))
+		assert.Equal(t, huawei.HuaweiOutputAverageRate(2048*1024), huawei.HuaweiOutputAverageRate_Get(p))
+		assert.Equal(t, huawei.HuaweiOutputPeakRate(2048*1024*4), huawei.HuaweiOutputPeakRate_Get(p))
+	})
+
+	t.Run("Huawei clamps large rates", func(t *testing.T) {
+		p := radius.New(radius.CodeCoARequest, []byte("secret"))
+		assert.True(t, SetRateLimit(p, CodeHuawei, math.MaxInt32, math.MaxInt32))
+		assert.Equal(t, huawei.HuaweiInputPeakRate(math.MaxInt32), huawei.HuaweiInputPeakRate_Get(p))
+	})
+
+	t.Run("Generic vendor", func(t *testing.T) {
+		p := radius.New(radius.CodeCoARequest, []byte("secret"))
+		assert.False(t, SetRateLimit(p, CodeStandard, 1024, 2048))
+		assert.False(t, SupportsRateLimit(CodeStandard))
+		_, found := p.Lookup(rfc2865.VendorSpecific_Type)
+		assert.False(t, found)
+	})
+}
+
+func TestAcceptRateLimitAttributes(t *testing.T) {
+	packetWith := func(attrs radius.Attributes) *radius.Packet {
+		p := radius.New(radius.CodeAccessAccept, []byte("secret"))
+		p.Attributes = attrs
+		return p
+	}
+
+	t.Run("Mikrotik", func(t *testing.T) {
+		p := packetWith(AcceptRateLimitAttributes(CodeMikrotik, 1024, 2048))
+		assert.Equal(t, "1024k/2048k", mikrotik.MikrotikRateLimit_GetString(p))
+	})
+
+	t.Run("Cisco", func(t *testing.T) {
+		p := packetWith(AcceptRateLimitAttributes(CodeCisco, 1024, 2048))
+		pairs, err := cisco.CiscoAVPair_GetStrings(p)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"lcp:interface-config#1=rate-limit input 1048576 196608 393216 conform-action transmit exceed-action drop",
+			"lcp:interface-config#2=rate-limit output 2097152 393216 786432 conform-action transmit exceed-action drop",
+		}, pairs)
+	})
+
+	t.Run("pfSense", func(t *testing.T) {
+		attrs := AcceptRateLimitAttributes(CodePfSense, 1024, 0)
+		require.Len(t, attrs, 1, "an unlimited direction adds no attribute")
+
+		vendorID, vsa, err := radius.VendorSpecific(attrs[0].Attribute)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(wisprVendorID), vendorID)
+		assert.Equal(t, byte(wisprBandwidthMaxUp), vsa[0])
+		assert.Equal(t, uint32(1024*1024), binary.BigEndian.Uint32(vsa[2:]))
+	})
+
+	t.Run("Vendors without rate attributes", func(t *testing.T) {
+		for _, vendorCode := range []string{CodeStandard, CodeJuniper, "14823" /* Aruba */} {
+			assert.Empty(t, AcceptRateLimitAttributes(vendorCode, 1024, 2048), "vendor %s", vendorCode)
+		}
+	})
+}
+
+func TestClampRate(t *testing.T) {
+	assert.Equal(t, int64(100), ClampRate(100))
+	assert.Equal(t, int64(-100), ClampRate(-100))
+	assert.Equal(t, int64(math.MaxInt32), ClampRate(math.MaxInt32))
+	assert.Equal(t, int64(math.MaxInt32), ClampRate(math.MaxInt32+1000))
+}
@@ -14,6 +14,8 @@ type VendorInfo struct {
 	Description string
 	Parser      vendorparsers.VendorParser
 	Builder     vendorparsers.VendorResponseBuilder
+	// Capabilities overrides the built-in feature flags when set
+	Capabilities *Capabilities
 }
 
 // VendorRegistry manages vendor registrations
@@ -50,6 +52,9 @@ func (r *VendorRegistry) Register(info *VendorInfo) error {
 		if info.Builder != nil {
 			existing.Builder = info.Builder
 		}
+		if info.Capabilities != nil {
+			existing.Capabilities = info.Capabilities
+		}
 		return nil
 	}
 
@@ -86,3 +86,20 @@ func TestGlobalRegistry(t *testing.T) {
 	list := List()
 	assert.Len(t, list, 1)
 }
+
+func TestVendorCapabilities(t *testing.T) {
+	registry := NewVendorRegistry()
+
+	assert.True(t, registry.Capabilities(CodeMikrotik).QoS)
+	assert.Equal(t, Capabilities{}, registry.Capabilities(CodeStandard))
+	assert.Equal(t, Capabilities{}, registry.Capabilities("99999"))
+
+	// A registered capability set overrides the built-in flags
+	err := registry.Register(&VendorInfo{Code: CodeHuawei, Name: "Huawei"})
+	assert.NoError(t, err)
+	assert.Equal(t, Capabilities{RateLimitCoA: true}, registry.Capabilities(CodeHuawei))
+
+	err = registry.Register(&VendorInfo{Code: CodeHuawei, Capabilities: &Capabilities{QoS: true}})
+	assert.NoError(t, err)
+	assert.Equal(t, Capabilities{QoS: true}, registry.Capabilities(CodeHuawei))
+}
@@ -150,6 +150,7 @@ const customEnglishMessages: TranslationMessages = {
         framed_netmask: 'Subnet Mask',
         mac_addr: 'MAC Address',
         session_timeout: 'Timeout(s)',
+        acct_interim_interval: 'Interim Interval(s)',
         acct_start_time: 'Start Time',
         acct_session_time: 'Session Time(s)',
         session_time: 'Online Duration',
@@ -320,6 +321,7 @@ const customEnglishMessages: TranslationMessages = {
         hostname: 'Hostname',
         secret: 'RADIUS Secret',
         coa_port: 'COA Port',
+        mac_auth_disabled: 'Disable MAC Authentication',
         vendor_code: 'Vendor Code',
         model: 'Device Model',
         tags: 'Tags',
@@ -362,6 +364,7 @@ const customEnglishMessages: TranslationMessages = {
         hostname: 'Optional hostname',
         secret: 'At least 6 characters',
         coa_port: '1-65535, default 3799',
+        mac_auth_disabled: 'Users of this device must authenticate with a password, requests whose username is the calling MAC are not MAC authenticated',
         tags: 'Comma-separated tags, max 200 characters',
         remark: 'Optional remark, max 500 characters',
         no_remark: 'No remark',
@@ -551,6 +554,10 @@ const customEnglishMessages: TranslationMessages = {
           title: 'Security Configuration',
           description: 'Security policy and authentication related configuration',
         },
+        qos: {
+          title: 'QoS Configuration',
+          description: 'QoS queue synchronization and backlog alerting',
+        },
       },
       value_range: 'Range',
       min: 'Min',
@@ -598,6 +605,42 @@ const customEnglishMessages: TranslationMessages = {
         description: 'Time window used to count consecutive rejects. Counters reset automatically after the window expires.',
       },
     },
+    qos: {
+      backlog_alert_threshold: {
+        title: 'Backlog Alert Threshold',
+        description: 'Number of pending or failed QoS queues on a single NAS that triggers a backlog alert. Use 0 to disable the alert.',
+      },
+      backlog_alert_minutes: {
+        title: 'Backlog Alert Duration (minutes)',
+        description: 'How long the backlog must stay above the threshold before the alert is raised.',
+      },
+      sync_paused: {
+        title: 'Pause QoS Sync',
+        description: 'Stops the periodic and manual QoS syncs to every NAS device, e.g. during maintenance. The QoS switch of each NAS is kept; queued changes are pushed once the sync is resumed.',
+      },
+      routeros_max_sessions: {
+        title: 'RouterOS Sessions per Device',
+        description: 'Maximum number of RouterOS API connections kept open to one device at the same time by QoS sync, live queue reads and profile pushes. A connection holds its session until it is closed; further connections wait for a free session.',
+      },
+    },
+    system: {
+      db_maintenance_enabled: {
+        title: 'Database Maintenance',
+        description: 'Runs a nightly maintenance task: VACUUM and ANALYZE on SQLite, ANALYZE on Postgres. Disabled by default.',
+      },
+      db_maintenance_vacuum: {
+        title: 'Postgres VACUUM',
+        description: 'Also runs VACUUM during Postgres maintenance. Can be slow on large tables.',
+      },
+      dashboard_stats_cache_seconds: {
+        title: 'Dashboard Cache TTL (seconds)',
+        description: 'How long the dashboard statistics are served from memory. Changes to users, profiles and sessions refresh them immediately. 0 disables the cache.',
+      },
+      online_stats_cache_seconds: {
+        title: 'Online Statistics Cache TTL (seconds)',
+        description: 'How long the online session statistics are served from memory. 0 disables the cache.',
+      },
+    },
   },
   common: {
     status: 'Status',
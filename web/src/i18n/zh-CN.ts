@@ -150,6 +150,7 @@ const customChineseMessages: TranslationMessages = {
         framed_netmask: '子网掩码',
         mac_addr: 'MAC地址',
         session_timeout: '超时时间(秒)',
+        acct_interim_interval: '计费更新间隔(秒)',
         acct_start_time: '开始时间',
         acct_session_time: '会话时长(秒)',
         session_time: '在线时长',
@@ -320,6 +321,7 @@ const customChineseMessages: TranslationMessages = {
         hostname: '设备主机地址',
         secret: 'RADIUS秘钥',
         coa_port: 'COA端口',
+        mac_auth_disabled: '禁用MAC认证',
         vendor_code: '厂商代码',
         model: '设备型号',
         tags: '标签',
@@ -362,6 +364,7 @@ const customChineseMessages: TranslationMessages = {
         hostname: '可选的主机名',
         secret: '至少6个字符',
         coa_port: '1-65535，默认3799',
+        mac_auth_disabled: '开启后该设备的用户只能使用密码认证，用户名为终端MAC的请求不再按MAC认证',
         tags: '多个标签用逗号分隔，最多200个字符',
         remark: '可选的备注信息，最多500个字符',
         no_remark: '无备注信息',
@@ -551,6 +554,10 @@ const customChineseMessages: TranslationMessages = {
           title: '安全配置',
           description: '安全策略和认证相关配置',
         },
+        qos: {
+          title: 'QoS 配置',
+          description: 'QoS 队列同步与积压告警相关配置',
+        },
       },
       value_range: '范围',
       min: '最小',
@@ -598,6 +605,42 @@ const customChineseMessages: TranslationMessages = {
         description: '统计连续拒绝次数的时间窗口（秒），超出窗口将自动清零计数',
       },
     },
+    qos: {
+      backlog_alert_threshold: {
+        title: '积压告警阈值',
+        description: '单台 NAS 待同步或失败的 QoS 队列数超过该值时触发告警，0 表示关闭告警',
+      },
+      backlog_alert_minutes: {
+        title: '积压告警持续时间（分钟）',
+        description: '积压持续超过阈值多长时间后才触发告警',
+      },
+      sync_paused: {
+        title: '暂停 QoS 同步',
+        description: '暂停向所有 NAS 设备的定时和手动 QoS 同步，例如维护期间。各 NAS 的 QoS 开关保持不变，恢复后继续推送排队的变更',
+      },
+      routeros_max_sessions: {
+        title: '单台设备 RouterOS 会话数',
+        description: 'QoS 同步、实时队列读取和配置推送对单台设备同时保持的 RouterOS API 连接上限，连接在关闭前一直占用会话，超出的连接将排队等待',
+      },
+    },
+    system: {
+      db_maintenance_enabled: {
+        title: '数据库维护',
+        description: '每晚执行数据库维护任务：SQLite 执行 VACUUM 和 ANALYZE，Postgres 执行 ANALYZE，默认关闭',
+      },
+      db_maintenance_vacuum: {
+        title: 'Postgres VACUUM',
+        description: 'Postgres 维护时同时执行 VACUUM，大表可能耗时较长',
+      },
+      dashboard_stats_cache_seconds: {
+        title: '仪表盘缓存时间（秒）',
+        description: '仪表盘统计数据在内存中缓存的时间，用户、套餐和会话变更后立即刷新，0 表示不缓存',
+      },
+      online_stats_cache_seconds: {
+        title: '在线统计缓存时间（秒）',
+        description: '在线会话统计数据在内存中缓存的时间，0 表示不缓存',
+      },
+    },
   },
   common: {
     status: '状态',
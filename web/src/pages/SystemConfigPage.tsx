@@ -33,6 +33,7 @@ import {
   Settings as SettingsIcon,
   Security as SecurityIcon,
   Router as RouterIcon,
+  Speed as SpeedIcon,
 } from '@mui/icons-material';
 import { useDataProvider, useNotify, useTranslate } from 'react-admin';
 import { useApiQuery } from '../hooks/useApiQuery';
@@ -125,6 +126,12 @@ export const SystemConfigPage: React.FC = () => {
       icon: <SecurityIcon />,
       color: '#d32f2f',
     },
+    qos: {
+      title: translate('pages.system_config.groups.qos.title'),
+      description: translate('pages.system_config.groups.qos.description'),
+      icon: <SpeedIcon />,
+      color: '#ed6c02',
+    },
   }), [translate]);
 
   const groupedSchemas = useMemo(() => {
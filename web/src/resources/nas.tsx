@@ -34,7 +34,8 @@ import {
   useRefresh,
   useNotify,
   RaRecord,
-  FunctionField
+  FunctionField,
+  BooleanInput
 } from 'react-admin';
 import {
   Box,
@@ -96,6 +97,7 @@ interface NASDevice extends RaRecord {
   vendor_code?: string;
   model?: string;
   coa_port?: number;
+  mac_auth_disabled?: boolean;
   status?: 'enabled' | 'disabled';
   node_id?: string;
   tags?: string;
@@ -881,6 +883,13 @@ export const NASEdit = () => {
                 <SelectInput optionText="name" fullWidth size="small" />
               </ReferenceInput>
             </FieldGridItem>
+            <FieldGridItem span={{ xs: 1, sm: 2 }}>
+              <BooleanInput
+                source="mac_auth_disabled"
+                label={translate('resources.network/nas.fields.mac_auth_disabled', { _: '禁用MAC认证' })}
+                helperText={translate('resources.network/nas.helpers.mac_auth_disabled', { _: '开启后该设备的用户只能使用密码认证' })}
+              />
+            </FieldGridItem>
             <FieldGridItem span={{ xs: 1, sm: 2 }}>
               <TextInput
                 source="tags"
@@ -1044,6 +1053,13 @@ export const NASCreate = () => {
                 <SelectInput optionText="name" fullWidth size="small" />
               </ReferenceInput>
             </FieldGridItem>
+            <FieldGridItem span={{ xs: 1, sm: 2 }}>
+              <BooleanInput
+                source="mac_auth_disabled"
+                label={translate('resources.network/nas.fields.mac_auth_disabled', { _: '禁用MAC认证' })}
+                helperText={translate('resources.network/nas.helpers.mac_auth_disabled', { _: '开启后该设备的用户只能使用密码认证' })}
+              />
+            </FieldGridItem>
             <FieldGridItem span={{ xs: 1, sm: 2 }}>
               <TextInput
                 source="tags"
@@ -75,6 +75,7 @@ interface OnlineSession extends RaRecord {
   service_type?: string;
   framed_netmask?: string;
   session_timeout?: number;
+  acct_interim_interval?: number;
   acct_start_time?: string | number;
   acct_session_time?: number;
   acct_input_total?: number | string;
@@ -976,6 +977,10 @@ const OnlineSessionDetails = () => {
                   : <EmptyValue message="无限制" />
               }
             />
+            <DetailItem
+              label={translate('resources.radius/online.fields.acct_interim_interval')}
+              value={record.acct_interim_interval ? `${record.acct_interim_interval}s` : <EmptyValue />}
+            />
           </Box>
         </DetailSectionCard>
 